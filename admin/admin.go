@@ -0,0 +1,335 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+The admin package provides a JSON-RPC API for inspecting and controlling a running relayer.
+
+Every request must carry the configured token as a bearer token in the Authorization header. Methods are served in
+the admin namespace:
+
+	admin_chains                          chains with their listener position and number of pending messages
+	admin_pendingMessages(chain)          messages routed to the chain's writer that have not been executed
+	admin_pauseListener(chain)            stop the chain's listener from processing further blocks
+	admin_resumeListener(chain)           resume a paused listener
+	admin_rescan(chain, block)            continue processing the chain from block, routing its deposits again
+	admin_resubmit(src, nonce, [dst])     resolve a message from src with the deposit nonce again
+	admin_quarantined(chain)              transfers from the chain held for exceeding its limits
+	admin_release(chain, dst, nonce)      route a quarantined transfer from the chain without checking the limits
+	admin_discard(chain, dst, nonce)      drop a quarantined transfer from the chain without routing it
+*/
+package admin
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var ErrNoToken = errors.New("admin API token not set")
+
+// Chain is a chain that can be inspected and controlled through the admin API
+type Chain interface {
+	Id() msg.ChainId
+	Name() string
+	ListenerControl() *chains.ListenerControl
+	MessageQueue() *msgqueue.Queue
+	Resubmit(m msg.Message)
+	TransferGate() *limits.Gate
+}
+
+// ChainInfo describes a chain and the position of its listener
+type ChainInfo struct {
+	Id      msg.ChainId `json:"id"`
+	Name    string      `json:"name"`
+	Block   *big.Int    `json:"block"` // Next block the listener will process
+	Paused  bool        `json:"paused"`
+	Pending int         `json:"pending"`
+}
+
+// Message describes a message in a writer's queue
+type Message struct {
+	Source       msg.ChainId      `json:"source"`
+	Destination  msg.ChainId      `json:"destination"`
+	DepositNonce msg.Nonce        `json:"depositNonce"`
+	Type         msg.TransferType `json:"type"`
+	ResourceId   string           `json:"resourceId"`
+	State        msgqueue.State   `json:"state"`
+	Updated      time.Time        `json:"updated"`
+}
+
+func newMessage(e msgqueue.Entry) Message {
+	return Message{
+		Source:       e.Message.Source,
+		Destination:  e.Message.Destination,
+		DepositNonce: e.Message.DepositNonce,
+		Type:         e.Message.Type,
+		ResourceId:   e.Message.ResourceId.Hex(),
+		State:        e.State,
+		Updated:      e.Updated,
+	}
+}
+
+// QuarantinedTransfer describes a transfer held in a chain's quarantine
+type QuarantinedTransfer struct {
+	Source       msg.ChainId      `json:"source"`
+	Destination  msg.ChainId      `json:"destination"`
+	DepositNonce msg.Nonce        `json:"depositNonce"`
+	Type         msg.TransferType `json:"type"`
+	ResourceId   string           `json:"resourceId"`
+	Amount       *big.Int         `json:"amount,omitempty"`
+	Recipient    string           `json:"recipient,omitempty"`
+	Reason       string           `json:"reason"`
+	Quarantined  time.Time        `json:"quarantined"`
+}
+
+func newQuarantinedTransfer(e limits.Entry) QuarantinedTransfer {
+	res := QuarantinedTransfer{
+		Source:       e.Message.Source,
+		Destination:  e.Message.Destination,
+		DepositNonce: e.Message.DepositNonce,
+		Type:         e.Message.Type,
+		ResourceId:   e.Message.ResourceId.Hex(),
+		Reason:       e.Reason,
+		Quarantined:  e.Quarantined,
+	}
+	if e.Message.Type == msg.FungibleTransfer {
+		res.Amount = new(big.Int).SetBytes(e.Message.Payload[0].([]byte))
+		res.Recipient = fmt.Sprintf("0x%x", e.Message.Payload[1].([]byte))
+	}
+	return res
+}
+
+// pendingEntries returns the entries of q that have not been executed, including failed ones. Voted entries are not
+// pending on writers that never execute proposals.
+func pendingEntries(q *msgqueue.Queue) []msgqueue.Entry {
+	res := make([]msgqueue.Entry, 0)
+	for _, e := range q.Entries(msgqueue.Received, msgqueue.Voted, msgqueue.Failed) {
+		if e.State == msgqueue.Voted && q.Finished(e.State) {
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// API implements the admin namespace
+type API struct {
+	chains map[msg.ChainId]Chain
+	log    log.Logger
+}
+
+func NewAPI(chains []Chain) *API {
+	api := &API{chains: make(map[msg.ChainId]Chain), log: log.Root().New("module", "admin")}
+	for _, c := range chains {
+		api.chains[c.Id()] = c
+	}
+	return api
+}
+
+func (a *API) chain(id msg.ChainId) (Chain, error) {
+	c, ok := a.chains[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown chain %d", id)
+	}
+	return c, nil
+}
+
+// Chains lists every chain, ordered by ID
+func (a *API) Chains() []ChainInfo {
+	res := make([]ChainInfo, 0, len(a.chains))
+	for id := 0; id <= 255; id++ {
+		c, ok := a.chains[msg.ChainId(id)]
+		if !ok {
+			continue
+		}
+		info := ChainInfo{
+			Id:     c.Id(),
+			Name:   c.Name(),
+			Block:  c.ListenerControl().Position(),
+			Paused: c.ListenerControl().Paused(),
+		}
+		if q := c.MessageQueue(); q != nil {
+			info.Pending = len(pendingEntries(q))
+		}
+		res = append(res, info)
+	}
+	return res
+}
+
+// PendingMessages lists the messages routed to the chain's writer that have not been executed, including failed ones
+func (a *API) PendingMessages(chain msg.ChainId) ([]Message, error) {
+	c, err := a.chain(chain)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Message, 0)
+	if q := c.MessageQueue(); q != nil {
+		for _, e := range pendingEntries(q) {
+			res = append(res, newMessage(e))
+		}
+	}
+	return res, nil
+}
+
+func (a *API) PauseListener(chain msg.ChainId) error {
+	c, err := a.chain(chain)
+	if err != nil {
+		return err
+	}
+	c.ListenerControl().Pause()
+	a.log.Info("Paused listener", "chain", c.Name())
+	return nil
+}
+
+func (a *API) ResumeListener(chain msg.ChainId) error {
+	c, err := a.chain(chain)
+	if err != nil {
+		return err
+	}
+	c.ListenerControl().Resume()
+	a.log.Info("Resumed listener", "chain", c.Name())
+	return nil
+}
+
+// Rescan moves the chain's listener to block. Deposits that were already routed are routed again, writers skip
+// proposals that are complete or already voted on.
+func (a *API) Rescan(chain msg.ChainId, block uint64) error {
+	c, err := a.chain(chain)
+	if err != nil {
+		return err
+	}
+	c.ListenerControl().Rescan(new(big.Int).SetUint64(block))
+	a.log.Info("Requested re-scan", "chain", c.Name(), "block", block)
+	return nil
+}
+
+// Resubmit resolves the message from src with the given deposit nonce again. As deposit nonces are counted per
+// destination, dst must be given if the message is queued for more than one chain.
+func (a *API) Resubmit(src msg.ChainId, nonce msg.Nonce, dst *msg.ChainId) (*Message, error) {
+	var found []Chain
+	var entry msgqueue.Entry
+	for _, c := range a.chains {
+		if dst != nil && c.Id() != *dst {
+			continue
+		}
+		q := c.MessageQueue()
+		if q == nil {
+			continue
+		}
+		if e, ok := q.Find(src, nonce); ok {
+			found = append(found, c)
+			entry = e
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no queued message from chain %d with nonce %d, use admin_rescan to route the deposit again", src, nonce)
+	case 1:
+		found[0].Resubmit(entry.Message)
+		a.log.Info("Re-submitted message", "src", src, "dst", found[0].Id(), "nonce", nonce)
+		res := newMessage(entry)
+		return &res, nil
+	default:
+		return nil, fmt.Errorf("message from chain %d with nonce %d is queued for %d chains, specify the destination", src, nonce, len(found))
+	}
+}
+
+// Quarantined lists the transfers from the chain held for exceeding its limits
+func (a *API) Quarantined(chain msg.ChainId) ([]QuarantinedTransfer, error) {
+	c, err := a.chain(chain)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]QuarantinedTransfer, 0)
+	if g := c.TransferGate(); g != nil {
+		for _, e := range g.Quarantined() {
+			res = append(res, newQuarantinedTransfer(e))
+		}
+	}
+	return res, nil
+}
+
+// Release routes the quarantined transfer from the chain to dst with the given deposit nonce. The transfer is not
+// checked against the limits again.
+func (a *API) Release(chain msg.ChainId, dst msg.ChainId, nonce msg.Nonce) (*QuarantinedTransfer, error) {
+	g, err := a.gate(chain)
+	if err != nil {
+		return nil, err
+	}
+	e, err := g.Release(dst, nonce)
+	if err != nil {
+		return nil, err
+	}
+	a.log.Info("Released quarantined transfer", "src", chain, "dst", dst, "nonce", nonce)
+	res := newQuarantinedTransfer(e)
+	return &res, nil
+}
+
+// Discard removes the quarantined transfer from the chain to dst with the given deposit nonce without routing it
+func (a *API) Discard(chain msg.ChainId, dst msg.ChainId, nonce msg.Nonce) (*QuarantinedTransfer, error) {
+	g, err := a.gate(chain)
+	if err != nil {
+		return nil, err
+	}
+	e, err := g.Discard(dst, nonce)
+	if err != nil {
+		return nil, err
+	}
+	a.log.Info("Discarded quarantined transfer", "src", chain, "dst", dst, "nonce", nonce)
+	res := newQuarantinedTransfer(e)
+	return &res, nil
+}
+
+func (a *API) gate(id msg.ChainId) (*limits.Gate, error) {
+	c, err := a.chain(id)
+	if err != nil {
+		return nil, err
+	}
+	g := c.TransferGate()
+	if g == nil {
+		return nil, fmt.Errorf("chain %d has no transfer limits", id)
+	}
+	return g, nil
+}
+
+// NewHandler returns an http.Handler serving the admin API for chains. Requests without the token are rejected.
+func NewHandler(token string, chains []Chain) (http.Handler, error) {
+	if token == "" {
+		return nil, ErrNoToken
+	}
+
+	server := rpc.NewServer()
+	err := server.RegisterName("admin", NewAPI(chains))
+	if err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			log.Warn("Rejected unauthorized admin API request", "remote", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}), nil
+}
+
+func authorized(r *http.Request, token string) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) == 1
+}
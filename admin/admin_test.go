@@ -0,0 +1,203 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package admin
+
+import (
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testChain struct {
+	id          msg.ChainId
+	control     chains.ListenerControl
+	queue       *msgqueue.Queue
+	gate        *limits.Gate
+	resubmitted []msg.Message
+}
+
+func (c *testChain) Id() msg.ChainId                          { return c.id }
+func (c *testChain) Name() string                             { return "test" }
+func (c *testChain) ListenerControl() *chains.ListenerControl { return &c.control }
+func (c *testChain) MessageQueue() *msgqueue.Queue            { return c.queue }
+func (c *testChain) Resubmit(m msg.Message)                   { c.resubmitted = append(c.resubmitted, m) }
+func (c *testChain) TransferGate() *limits.Gate               { return c.gate }
+
+func newTestServer(t *testing.T, token string) (*testChain, *rpc.Client, func()) {
+	dir, err := ioutil.TempDir(os.TempDir(), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := msgqueue.NewQueue(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &testChain{id: 1, queue: q}
+
+	h, err := NewHandler("secret", []Chain{chain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+
+	client, err := rpc.DialHTTP(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHeader("Authorization", "Bearer "+token)
+
+	return chain, client, func() {
+		client.Close()
+		srv.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, client, cleanup := newTestServer(t, "wrong")
+	defer cleanup()
+
+	var res []ChainInfo
+	err := client.Call(&res, "admin_chains")
+	if err == nil {
+		t.Fatal("expected request with wrong token to be rejected")
+	}
+}
+
+func TestAdminAPI(t *testing.T) {
+	chain, client, cleanup := newTestServer(t, "secret")
+	defer cleanup()
+
+	m := msg.NewFungibleTransfer(0, 1, 5, big.NewInt(10), msg.ResourceId{1}, []byte{2})
+	_, err := chain.queue.Add(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.control.SetPosition(big.NewInt(100))
+
+	err = client.Call(nil, "admin_pauseListener", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var infos []ChainInfo
+	err = client.Call(&infos, "admin_chains")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || !infos[0].Paused || infos[0].Block.Cmp(big.NewInt(100)) != 0 || infos[0].Pending != 1 {
+		t.Fatalf("unexpected chains: %+v", infos)
+	}
+
+	err = client.Call(nil, "admin_rescan", 1, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block := chain.control.TakeRescan(); block == nil || block.Cmp(big.NewInt(50)) != 0 {
+		t.Fatalf("expected re-scan from block 50, got %v", block)
+	}
+
+	var pending []Message
+	err = client.Call(&pending, "admin_pendingMessages", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].DepositNonce != 5 || pending[0].State != msgqueue.Received {
+		t.Fatalf("unexpected pending messages: %+v", pending)
+	}
+
+	var resubmitted Message
+	err = client.Call(&resubmitted, "admin_resubmit", 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.resubmitted) != 1 || chain.resubmitted[0].DepositNonce != 5 {
+		t.Fatalf("expected message to be resubmitted, got %v", chain.resubmitted)
+	}
+
+	err = client.Call(&resubmitted, "admin_resubmit", 0, 6)
+	if err == nil {
+		t.Fatal("expected resubmitting an unknown message to fail")
+	}
+	err = client.Call(nil, "admin_pauseListener", 2)
+	if err == nil {
+		t.Fatal("expected pausing an unknown chain to fail")
+	}
+}
+
+type recordingRouter struct {
+	sent []msg.Message
+}
+
+func (r *recordingRouter) Send(m msg.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestQuarantine(t *testing.T) {
+	chain, client, cleanup := newTestServer(t, "secret")
+	defer cleanup()
+
+	err := client.Call(nil, "admin_release", 1, 2, 5)
+	if err == nil {
+		t.Fatal("expected release on a chain without limits to fail")
+	}
+
+	dir, err := ioutil.TempDir(os.TempDir(), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	q, err := limits.NewQuarantine(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := limits.NewWindow(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rId := msg.ResourceId{1}
+	chain.gate = limits.NewGate(limits.Limits{rId: {MaxAmount: big.NewInt(10)}}, q, w, log15.New())
+	router := &recordingRouter{}
+	chain.gate.SetRouter(router)
+	for nonce := msg.Nonce(5); nonce <= 6; nonce++ {
+		err = chain.gate.Send(msg.NewFungibleTransfer(1, 2, nonce, big.NewInt(11), rId, []byte{0xab}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var quarantined []QuarantinedTransfer
+	err = client.Call(&quarantined, "admin_quarantined", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quarantined) != 2 || quarantined[0].DepositNonce != 5 || quarantined[0].Amount.Int64() != 11 || quarantined[0].Recipient != "0xab" || quarantined[0].Reason == "" {
+		t.Fatalf("unexpected quarantined transfers: %+v", quarantined)
+	}
+
+	var released QuarantinedTransfer
+	err = client.Call(&released, "admin_release", 1, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(router.sent) != 1 || router.sent[0].DepositNonce != 5 {
+		t.Fatalf("expected released transfer to be routed, got %v", router.sent)
+	}
+	err = client.Call(&released, "admin_discard", 1, 2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(router.sent) != 1 || len(chain.gate.Quarantined()) != 0 {
+		t.Fatalf("expected discarded transfer to be dropped, routed %v", router.sent)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+The amount package converts fungible transfer amounts between chains whose tokens have different decimals.
+
+Amounts are scaled by exact powers of ten. Converting to fewer decimals may leave a remainder, the dust, that cannot
+be represented on the destination chain. How dust is handled is set by the DustPolicy:
+
+	reject:   the transfer is not relayed
+	truncate: the dust is dropped
+	refund:   the dust is dropped and flagged for refund by the operators
+*/
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+type DustPolicy string
+
+const (
+	DustReject   DustPolicy = "reject"
+	DustTruncate DustPolicy = "truncate"
+	DustRefund   DustPolicy = "refund"
+)
+
+// DefaultDustPolicy drops dust, as amounts were always truncated before the policy could be configured
+const DefaultDustPolicy = DustTruncate
+
+// ErrDust is returned for amounts with dust when the policy is to reject them
+var ErrDust = errors.New("amount cannot be represented with the destination decimals")
+
+// ParseDustPolicy parses the name of a DustPolicy
+func ParseDustPolicy(s string) (DustPolicy, error) {
+	switch p := DustPolicy(strings.ToLower(s)); p {
+	case DustReject, DustTruncate, DustRefund:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown dust policy %q, expected %s, %s or %s", s, DustReject, DustTruncate, DustRefund)
+	}
+}
+
+// Scale converts amount from from decimals to to decimals. The dust is the remainder of amount, in from decimals,
+// that was dropped, which is zero unless to is less than from.
+func Scale(amount *big.Int, from, to uint8) (scaled *big.Int, dust *big.Int) {
+	if from == to {
+		return new(big.Int).Set(amount), new(big.Int)
+	}
+	if from < to {
+		return new(big.Int).Mul(amount, pow10(to-from)), new(big.Int)
+	}
+	return new(big.Int).QuoRem(amount, pow10(from-to), new(big.Int))
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Conversion is an amount converted to the decimals of the destination chain
+type Conversion struct {
+	Amount *big.Int // Amount in destination decimals
+	Dust   *big.Int // Remainder in source decimals that was dropped, zero if there is none
+	Refund bool     // Whether the dust is flagged for refund
+}
+
+// Converter converts the amounts of transfers from one chain with the decimals configured for each destination chain
+// and resource
+type Converter struct {
+	decimals map[msg.ChainId]map[string][2]uint8 // Source and destination decimals by resource ID without 0x prefix
+	policy   DustPolicy
+}
+
+// NewConverter creates a Converter from the decimals of a chain's config, which map each destination chain and
+// resource ID to the source and destination decimals. Resources without decimals are not converted.
+func NewConverter(decimals map[msg.ChainId]map[string][2]uint8, policy DustPolicy) *Converter {
+	c := &Converter{decimals: make(map[msg.ChainId]map[string][2]uint8), policy: policy}
+	for dest, resources := range decimals {
+		c.decimals[dest] = make(map[string][2]uint8)
+		for rId, d := range resources {
+			c.decimals[dest][strings.ToLower(strings.TrimPrefix(rId, "0x"))] = d
+		}
+	}
+	return c
+}
+
+// Convert converts amount of resource rId for a transfer to dest. ErrDust is returned if the policy is to reject
+// amounts with dust.
+func (c *Converter) Convert(dest msg.ChainId, rId msg.ResourceId, amount *big.Int) (Conversion, error) {
+	d, ok := c.decimals[dest][rId.Hex()]
+	if !ok {
+		return Conversion{Amount: new(big.Int).Set(amount), Dust: new(big.Int)}, nil
+	}
+	return c.convert(amount, d[0], d[1])
+}
+
+// ConvertIncoming converts amount of resource rId for a transfer from src, using the decimals configured for src
+// in reverse. ErrDust is returned if the policy is to reject amounts with dust.
+func (c *Converter) ConvertIncoming(src msg.ChainId, rId msg.ResourceId, amount *big.Int) (Conversion, error) {
+	d, ok := c.decimals[src][rId.Hex()]
+	if !ok {
+		return Conversion{Amount: new(big.Int).Set(amount), Dust: new(big.Int)}, nil
+	}
+	return c.convert(amount, d[1], d[0])
+}
+
+func (c *Converter) convert(amount *big.Int, from, to uint8) (Conversion, error) {
+	scaled, dust := Scale(amount, from, to)
+	if dust.Sign() == 0 {
+		return Conversion{Amount: scaled, Dust: dust}, nil
+	}
+	if c.policy == DustReject {
+		return Conversion{}, fmt.Errorf("%w: %s with %d decimals leaves %s at %d decimals", ErrDust, amount, from, dust, to)
+	}
+	return Conversion{Amount: scaled, Dust: dust, Refund: c.policy == DustRefund}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package amount
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+func parseInt(t *testing.T, s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid number %s", s)
+	}
+	return n
+}
+
+func TestScale(t *testing.T) {
+	testCases := []struct {
+		amount   string
+		from, to uint8
+		scaled   string
+		dust     string
+	}{
+		{amount: "1000000000000000000", from: 18, to: 18, scaled: "1000000000000000000", dust: "0"},
+		{amount: "1500000", from: 6, to: 18, scaled: "1500000000000000000", dust: "0"},
+		{amount: "1500000000000000001", from: 18, to: 6, scaled: "1500000", dust: "1"},
+		// Differences beyond the range of int64 and amounts beyond float64 precision are exact
+		{amount: "123456789012345678901234567890", from: 0, to: 30, scaled: "123456789012345678901234567890000000000000000000000000000000", dust: "0"},
+		{amount: "123456789012345678901234567890123456789", from: 36, to: 0, scaled: "123", dust: "456789012345678901234567890123456789"},
+		{amount: "999", from: 24, to: 0, scaled: "0", dust: "999"},
+	}
+
+	for _, tc := range testCases {
+		scaled, dust := Scale(parseInt(t, tc.amount), tc.from, tc.to)
+		if scaled.String() != tc.scaled || dust.String() != tc.dust {
+			t.Errorf("%s from %d to %d decimals: expected %s and dust %s, got %s and dust %s", tc.amount, tc.from, tc.to, tc.scaled, tc.dust, scaled, dust)
+		}
+	}
+}
+
+func TestConverter(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{0xAB, 1})
+	other := msg.ResourceIdFromSlice([]byte{2})
+	decimals := map[msg.ChainId]map[string][2]uint8{2: {"0x" + rId.Hex(): {18, 6}}}
+	amount := parseInt(t, "1000000000000000123")
+
+	conv, err := NewConverter(decimals, DustTruncate).Convert(2, rId, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.Amount.String() != "1000000" || conv.Dust.String() != "123" || conv.Refund {
+		t.Fatalf("unexpected truncated conversion %+v", conv)
+	}
+
+	conv, err = NewConverter(decimals, DustRefund).Convert(2, rId, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.Amount.String() != "1000000" || conv.Dust.String() != "123" || !conv.Refund {
+		t.Fatalf("unexpected refunded conversion %+v", conv)
+	}
+
+	reject := NewConverter(decimals, DustReject)
+	if _, err = reject.Convert(2, rId, amount); !errors.Is(err, ErrDust) {
+		t.Fatalf("expected ErrDust, got %v", err)
+	}
+	conv, err = reject.Convert(2, rId, parseInt(t, "2000000000000000000"))
+	if err != nil || conv.Amount.String() != "2000000" {
+		t.Fatalf("expected whole amount to be converted, got %+v %v", conv, err)
+	}
+
+	// Other resources and destinations are unchanged
+	for _, c := range []struct {
+		dest msg.ChainId
+		rId  msg.ResourceId
+	}{{2, other}, {3, rId}} {
+		conv, err = reject.Convert(c.dest, c.rId, amount)
+		if err != nil || conv.Amount.Cmp(amount) != 0 || conv.Dust.Sign() != 0 {
+			t.Fatalf("expected amount unchanged for %d %x, got %+v %v", c.dest, c.rId, conv, err)
+		}
+	}
+}
+
+func TestConvertIncoming(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	// Decimals of a substrate chain with 12 decimals, for transfers to and from an ERC20 with 18
+	c := NewConverter(map[msg.ChainId]map[string][2]uint8{2: {rId.Hex(): {12, 18}}}, DustReject)
+
+	conv, err := c.Convert(2, rId, parseInt(t, "1500000000000"))
+	if err != nil || conv.Amount.String() != "1500000000000000000" {
+		t.Fatalf("expected outgoing amount of 1500000000000000000, got %+v %v", conv, err)
+	}
+	conv, err = c.ConvertIncoming(2, rId, parseInt(t, "1500000000000000000"))
+	if err != nil || conv.Amount.String() != "1500000000000" {
+		t.Fatalf("expected incoming amount of 1500000000000, got %+v %v", conv, err)
+	}
+	if _, err = c.ConvertIncoming(2, rId, parseInt(t, "1500000000000000001")); !errors.Is(err, ErrDust) {
+		t.Fatalf("expected ErrDust, got %v", err)
+	}
+	conv, err = c.ConvertIncoming(3, rId, parseInt(t, "7"))
+	if err != nil || conv.Amount.String() != "7" {
+		t.Fatalf("expected amount from other chains unchanged, got %+v %v", conv, err)
+	}
+}
+
+func TestParseDustPolicy(t *testing.T) {
+	for _, s := range []string{"reject", "Truncate", "REFUND"} {
+		if _, err := ParseDustPolicy(s); err != nil {
+			t.Errorf("%s: %s", s, err)
+		}
+	}
+	if _, err := ParseDustPolicy("round"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
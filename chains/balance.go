@@ -0,0 +1,98 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BalancePollInterval is the time between checks of the relayer balance
+var BalancePollInterval = time.Minute
+
+// BalanceStatus is the most recently fetched relayer balance
+type BalanceStatus struct {
+	Balance   *big.Int // nil until the balance has been fetched
+	Threshold *big.Int // nil if no threshold is configured
+	Low       bool     // Whether the balance is below the threshold
+}
+
+// BalanceMonitor periodically fetches the balance of the relayer account and warns while it is below a threshold
+type BalanceMonitor struct {
+	fetch     func() (*big.Int, error)
+	threshold *big.Int
+	gauge     prometheus.Gauge
+	log       log15.Logger
+	stop      <-chan int
+	lock      sync.RWMutex
+	balance   *big.Int
+}
+
+// NewBalanceMonitor creates a monitor of the balance returned by fetch. A nil threshold disables the warnings.
+func NewBalanceMonitor(fetch func() (*big.Int, error), threshold *big.Int, log log15.Logger, stop <-chan int) *BalanceMonitor {
+	return &BalanceMonitor{
+		fetch:     fetch,
+		threshold: threshold,
+		log:       log,
+		stop:      stop,
+	}
+}
+
+// RegisterMetrics exports the balance as the <chain>_relayer_balance gauge
+func (b *BalanceMonitor) RegisterMetrics(chain string) {
+	b.gauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: chain + "_relayer_balance",
+		Help: "Balance of the relayer account in the smallest unit of the native token",
+	})
+	prometheus.MustRegister(b.gauge)
+}
+
+// Start checks the balance every BalancePollInterval until stop is closed
+func (b *BalanceMonitor) Start() {
+	go func() {
+		for {
+			b.check()
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(BalancePollInterval):
+			}
+		}
+	}()
+}
+
+func (b *BalanceMonitor) check() {
+	balance, err := b.fetch()
+	if err != nil {
+		b.log.Warn("Failed to fetch relayer balance", "err", err)
+		return
+	}
+
+	b.lock.Lock()
+	b.balance = balance
+	b.lock.Unlock()
+
+	if b.gauge != nil {
+		f, _ := new(big.Float).SetInt(balance).Float64()
+		b.gauge.Set(f)
+	}
+	if b.Status().Low {
+		b.log.Warn("Relayer balance is low", "balance", balance, "threshold", b.threshold)
+	}
+}
+
+// Status returns the most recently fetched balance and whether it is below the threshold
+func (b *BalanceMonitor) Status() BalanceStatus {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return BalanceStatus{
+		Balance:   b.balance,
+		Threshold: b.threshold,
+		Low:       b.balance != nil && b.threshold != nil && b.balance.Cmp(b.threshold) == -1,
+	}
+}
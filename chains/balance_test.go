@@ -0,0 +1,48 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/log15"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	balance := big.NewInt(100)
+	var fetchErr error
+	fetch := func() (*big.Int, error) { return balance, fetchErr }
+
+	b := NewBalanceMonitor(fetch, big.NewInt(50), log15.Root(), nil)
+	if status := b.Status(); status.Balance != nil || status.Low {
+		t.Fatalf("expected unknown balance before the first check, got %+v", status)
+	}
+
+	b.check()
+	if status := b.Status(); status.Balance.Cmp(big.NewInt(100)) != 0 || status.Low {
+		t.Fatalf("expected balance of 100 above the threshold, got %+v", status)
+	}
+
+	balance = big.NewInt(10)
+	b.check()
+	if status := b.Status(); status.Balance.Cmp(big.NewInt(10)) != 0 || !status.Low {
+		t.Fatalf("expected low balance of 10, got %+v", status)
+	}
+
+	// A failed fetch keeps the last known balance
+	fetchErr = errors.New("unavailable")
+	b.check()
+	if status := b.Status(); status.Balance.Cmp(big.NewInt(10)) != 0 || !status.Low {
+		t.Fatalf("expected last known balance, got %+v", status)
+	}
+
+	// Without a threshold the balance is never low
+	b = NewBalanceMonitor(func() (*big.Int, error) { return big.NewInt(0), nil }, nil, log15.Root(), nil)
+	b.check()
+	if b.Status().Low {
+		t.Fatal("expected no low balance without a threshold")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"math/big"
+	"sync"
+)
+
+// ListenerControl is shared between a listener and the admin API, allowing the listener to be paused or moved to
+// another block while it runs. The zero value is an unpaused listener. It is safe for concurrent use.
+type ListenerControl struct {
+	lock     sync.Mutex
+	paused   bool
+	rescan   *big.Int // Block requested by Rescan, nil if none is pending
+	position *big.Int // Next block the listener will process
+}
+
+// Pause stops the listener from processing further blocks until Resume is called
+func (c *ListenerControl) Pause() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.paused = true
+}
+
+func (c *ListenerControl) Resume() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.paused = false
+}
+
+func (c *ListenerControl) Paused() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.paused
+}
+
+// Rescan requests the listener to continue processing from block. Deposits in blocks that were already processed
+// are routed again.
+func (c *ListenerControl) Rescan(block *big.Int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rescan = new(big.Int).Set(block)
+}
+
+// TakeRescan returns the block requested by Rescan, or nil if there is none, and clears the request
+func (c *ListenerControl) TakeRescan() *big.Int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	block := c.rescan
+	c.rescan = nil
+	return block
+}
+
+// SetPosition records the next block the listener will process
+func (c *ListenerControl) SetPosition(block *big.Int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.position = new(big.Int).Set(block)
+}
+
+// Position returns the next block the listener will process, or nil if it has not started
+func (c *ListenerControl) Position() *big.Int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.position == nil {
+		return nil
+	}
+	return new(big.Int).Set(c.position)
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
+)
+
+// pendingWork describes a message a writer is submitting transactions for
+type pendingWork struct {
+	task string
+	m    msg.Message
+}
+
+// Drainer tracks the work a writer is performing for messages, so the writer can stop accepting messages and wait
+// for the work to complete when it is stopped
+type Drainer struct {
+	log      log15.Logger
+	work     string        // Name of the submitted work in logs, eg. "transactions"
+	draining chan struct{} // Closed when the writer stops accepting messages
+	pending  map[uint64]pendingWork
+	nextId   uint64
+	lock     sync.Mutex
+	idle     chan struct{} // Signalled when the last pending work completes
+}
+
+func NewDrainer(log log15.Logger, work string) *Drainer {
+	return &Drainer{
+		log:      log,
+		work:     work,
+		draining: make(chan struct{}),
+		pending:  make(map[uint64]pendingWork),
+		idle:     make(chan struct{}, 1),
+	}
+}
+
+// Track records that task is being performed for m. The returned func must be called once the task completes. If
+// the drainer is draining nothing is recorded and nil is returned, so no work can start once Drain has returned.
+func (d *Drainer) Track(task string, m msg.Message) func() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.IsDraining() {
+		return nil
+	}
+	id := d.nextId
+	d.nextId++
+	d.pending[id] = pendingWork{task: task, m: m}
+
+	return func() {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		delete(d.pending, id)
+		if len(d.pending) == 0 {
+			select {
+			case d.idle <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Draining returns a channel that is closed once the drainer starts draining
+func (d *Drainer) Draining() <-chan struct{} {
+	return d.draining
+}
+
+func (d *Drainer) IsDraining() bool {
+	select {
+	case <-d.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain stops new work from being tracked and waits up to timeout for pending work to complete. Any work still
+// pending after the timeout is logged.
+func (d *Drainer) Drain(timeout time.Duration) {
+	d.lock.Lock()
+	close(d.draining)
+	d.lock.Unlock()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		d.lock.Lock()
+		remaining := len(d.pending)
+		d.lock.Unlock()
+		if remaining == 0 {
+			d.log.Info("Writer drained")
+			return
+		}
+
+		d.log.Info("Waiting for pending "+d.work, "count", remaining)
+		select {
+		case <-d.idle:
+		case <-deadline.C:
+			d.lock.Lock()
+			for _, p := range d.pending {
+				MessageLogger(d.log, p.m).Warn("Shutdown timeout exceeded, work unfinished", "task", p.task, "src", p.m.Source, "dst", p.m.Destination, "nonce", p.m.DepositNonce)
+			}
+			d.lock.Unlock()
+			return
+		}
+	}
+}
+
+// Checks are the optional checks a writer performs on a message before voting on it, unset checks are skipped
+type Checks struct {
+	Queue    *msgqueue.Queue  // Records the message, and its failure
+	Policy   *policy.Policy   // Must allow the message
+	Verifier *Verifier        // Must verify the deposit of the message on the source chain
+	Reorged  *ReorgedDeposits // Must not contain the message
+}
+
+// Admit records m in the queue and tracks it as task, unless the drainer is draining. It then checks that the policy
+// allows m, that its deposit is verified on the source chain and that the deposit was not reorganized. Messages that
+// fail a check are marked failed in the queue. Returns nil if m is not admitted, otherwise the returned func must be
+// called once m is resolved.
+func (d *Drainer) Admit(m msg.Message, task string, c Checks, log log15.Logger) func() {
+	if c.Queue != nil {
+		_, err := c.Queue.Add(m)
+		if err != nil {
+			log.Error("Failed to persist message", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		}
+	}
+
+	done := d.Track(task, m)
+	if done == nil {
+		log.Warn("Writer is shutting down, not resolving message", "src", m.Source, "nonce", m.DepositNonce)
+		return nil
+	}
+	reject := func() {
+		done()
+		if c.Queue == nil {
+			return
+		}
+		err := c.Queue.SetState(m, msgqueue.Failed)
+		if err != nil {
+			log.Error("Failed to update message queue", "src", m.Source, "nonce", m.DepositNonce, "state", msgqueue.Failed, "err", err)
+		}
+	}
+
+	if !c.Policy.Allows(m, log) {
+		reject()
+		return nil
+	}
+	if c.Verifier != nil {
+		if err := c.Verifier.Verify(m, d.draining); errors.Is(err, ErrVerifyStopped) {
+			log.Warn("Writer is shutting down, not resolving message", "src", m.Source, "nonce", m.DepositNonce)
+			done()
+			return nil
+		} else if err != nil {
+			log.Error("Failed to verify deposit on the source chain, not voting", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+			reject()
+			return nil
+		}
+	}
+	if c.Reorged.Contains(m) {
+		log.Error("Deposit is no longer on the canonical chain of the source, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		reject()
+		return nil
+	}
+	return done
+}
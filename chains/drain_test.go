@@ -0,0 +1,105 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+)
+
+func TestDrainerTrack(t *testing.T) {
+	d := NewDrainer(log15.Root(), "transactions")
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab})
+
+	done := d.Track("execute", m)
+	if done == nil {
+		t.Fatal("work was not tracked")
+	}
+	drained := make(chan struct{})
+	go func() {
+		d.Drain(time.Second * 5)
+		close(drained)
+	}()
+
+	// No work can be tracked once draining started
+	<-d.Draining()
+	if d.Track("execute", m) != nil {
+		t.Fatal("draining drainer tracked work")
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned with work pending")
+	default:
+	}
+
+	done()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return after pending work completed")
+	}
+}
+
+func TestDrainerTimeout(t *testing.T) {
+	d := NewDrainer(log15.Root(), "transactions")
+	d.Track("execute", msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab}))
+
+	start := time.Now()
+	d.Drain(time.Millisecond * 100)
+	if time.Since(start) < time.Millisecond*100 {
+		t.Fatal("drain returned before the timeout")
+	}
+}
+
+func TestDrainerAdmit(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "drain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	q, err := msgqueue.NewQueue(dir, 2, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reorged := NewReorgedDeposits()
+	c := Checks{Queue: q, Reorged: reorged}
+	d := NewDrainer(log15.Root(), "transactions")
+
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab})
+	done := d.Admit(m, "resolve", c, log15.Root())
+	if done == nil {
+		t.Fatal("message was not admitted")
+	}
+	if state, _ := q.State(m); state != msgqueue.Received {
+		t.Fatalf("unexpected state %s", state)
+	}
+	done()
+
+	// A reorganized deposit is failed
+	reorged.Add(m)
+	if d.Admit(m, "resolve", c, log15.Root()) != nil {
+		t.Fatal("reorganized deposit was admitted")
+	}
+	if state, _ := q.State(m); state != msgqueue.Failed {
+		t.Fatalf("unexpected state %s", state)
+	}
+
+	// Messages are recorded but not admitted while draining
+	d.Drain(time.Second)
+	refused := msg.NewFungibleTransfer(1, 2, 4, big.NewInt(100), verifyResource, []byte{0xab})
+	if d.Admit(refused, "resolve", c, log15.Root()) != nil {
+		t.Fatal("draining drainer admitted a message")
+	}
+	if state, _ := q.State(refused); state != msgqueue.Received {
+		t.Fatalf("unexpected state %s", state)
+	}
+}
@@ -21,8 +21,12 @@ The writer recieves the message and creates a proposals on-chain. Once a proposa
 package ethereum
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/blockstore"
@@ -35,15 +39,24 @@ import (
 	erc20Handler "github.com/UltronFoundationDev/chainbridge/bindings/ERC20Handler"
 	erc721Handler "github.com/UltronFoundationDev/chainbridge/bindings/ERC721Handler"
 	"github.com/UltronFoundationDev/chainbridge/bindings/GenericHandler"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
 	connection "github.com/UltronFoundationDev/chainbridge/connections/ethereum"
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 var _ core.Chain = &Chain{}
 
+// DefaultShutdownTimeout is how long Stop waits for pending votes and executions
+const DefaultShutdownTimeout = time.Second * 30
+
 var _ Connection = &connection.Connection{}
 
 type Connection interface {
@@ -54,24 +67,36 @@ type Connection interface {
 	LockAndUpdateOpts() error
 	UnlockOpts()
 	Client() *ethclient.Client
+	Backend() bind.ContractBackend
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
 	EnsureHasBytecode(address common.Address) error
 	LatestBlock() (*big.Int, error)
 	WaitForBlock(block *big.Int, delay *big.Int) error
+	EstimateGasLimit(to common.Address, data []byte) (uint64, error)
+	ReplaceTx(tx *types.Transaction) (*types.Transaction, error)
+	RevertReason(tx *types.Transaction, receipt *types.Receipt) string
+	Balance() (*big.Int, error)
 	Close()
 }
 
 type Chain struct {
-	cfg      *core.ChainConfig // The config of the chain
-	conn     Connection        // THe chains connection
-	listener *listener         // The listener of this chain
-	writer   *writer           // The writer of the chain
-	stop     chan<- int
+	cfg             *core.ChainConfig // The config of the chain
+	config          *Config           // The options the chain was started with, updated by Reload
+	conn            Connection        // THe chains connection
+	listener        *listener         // The listener of this chain
+	writer          *writer           // The writer of the chain
+	stop            chan<- int        // Stops the listener
+	stopListener    sync.Once
+	writerStop      chan<- int    // Stops the writer
+	shutdownTimeout time.Duration // Time the writer is given to finish pending work when stopping
+	balance         *chains.BalanceMonitor
+	gate            *limits.Gate // Checks transfers against the limits before routing, nil if there are none
 }
 
 // checkBlockstore queries the blockstore for the latest known block. If the latest block is
 // greater than cfg.startBlock, then cfg.startBlock is replaced with the latest known block.
-func setupBlockstore(cfg *Config, kp *secp256k1.Keypair) (*blockstore.Blockstore, error) {
-	bs, err := blockstore.NewBlockstore(cfg.blockstorePath, cfg.id, kp.Address())
+func setupBlockstore(cfg *Config, relayer string) (*blockstore.Blockstore, error) {
+	bs, err := blockstore.NewBlockstore(cfg.blockstorePath, cfg.id, relayer)
 	if err != nil {
 		return nil, err
 	}
@@ -90,73 +115,158 @@ func setupBlockstore(cfg *Config, kp *secp256k1.Keypair) (*blockstore.Blockstore
 	return bs, nil
 }
 
-func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics) (*Chain, error) {
-	cfg, err := parseChainConfig(chainCfg)
+// checkContracts ensures the configured contracts are deployed and the bridge reports the configured chain ID
+func checkContracts(conn *connection.Connection, cfg *Config) error {
+	err := conn.EnsureHasBytecode(cfg.bridgeContract)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if cfg.erc20HandlerContract != utils.ZeroAddress {
+		err = conn.EnsureHasBytecode(cfg.erc20HandlerContract)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.genericHandlerContract != utils.ZeroAddress {
+		err = conn.EnsureHasBytecode(cfg.genericHandlerContract)
+		if err != nil {
+			return err
+		}
+	}
+
+	bridgeContract, err := bridge.NewBridge(cfg.bridgeContract, conn.Backend())
+	if err != nil {
+		return err
+	}
+
+	chainId, err := bridgeContract.ChainID(conn.CallOpts())
+	if err != nil {
+		return err
+	}
+
+	if chainId != uint8(cfg.id) {
+		return fmt.Errorf("chainId (%d) and configuration chainId (%d) do not match", chainId, cfg.id)
+	}
+	return nil
+}
+
+// contracts are the bridge and handler contracts of a chain
+type contracts struct {
+	bridge         *bridge.Bridge
+	erc20Handler   *erc20Handler.ERC20Handler
+	erc721Handler  *erc721Handler.ERC721Handler
+	genericHandler *GenericHandler.GenericHandler
+}
+
+// newGasPriceOracle returns the median of the gas price oracles selected in cfg
+func newGasPriceOracle(cfg *Config, backend oracle.NodeBackend, logger log15.Logger) oracle.GasPriceOracle {
+	var oracles []oracle.GasPriceOracle
+	usesEGS := false
+	for _, name := range cfg.gasPriceOracles {
+		switch name {
+		case oracle.NodeOracle:
+			oracles = append(oracles, oracle.NewNode(backend, cfg.feeHistoryBlocks, cfg.gasPricePercentile))
+		case oracle.HTTPOracle:
+			oracles = append(oracles, oracle.NewHTTP(cfg.gasPriceOracleUrl, cfg.gasPriceOracleJsonPath, cfg.gasPriceOracleUnit))
+		case oracle.FixedOracle:
+			oracles = append(oracles, oracle.NewFixed(cfg.fixedGasPrice))
+		case oracle.EGSOracle:
+			oracles = append(oracles, oracle.NewEGS(cfg.egsApiKey, cfg.egsSpeed))
+			usesEGS = true
+		}
+	}
+	if cfg.egsApiKey != "" && !usesEGS {
+		logger.Warn("Ignoring egsApiKey, add egs to gasPriceOracles to use Eth Gas Station", "gasPriceOracles", strings.Join(cfg.gasPriceOracles, ","))
 	}
+	return oracle.NewMedian(logger, oracles...)
+}
 
-	kpI, err := keystore.KeypairFromAddress(cfg.from, keystore.EthChain, cfg.keystorePath, chainCfg.Insecure)
+// bindContracts binds the configured bridge and handler contracts to the connection
+func bindContracts(conn *connection.Connection, cfg *Config) (*contracts, error) {
+	bridgeContract, err := bridge.NewBridge(cfg.bridgeContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
-	kp, _ := kpI.(*secp256k1.Keypair)
 
-	bs, err := setupBlockstore(cfg, kp)
+	erc20HandlerContract, err := erc20Handler.NewERC20Handler(cfg.erc20HandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
 
-	stop := make(chan int)
-	conn := connection.NewConnection(cfg.endpoint, cfg.http, kp, logger, cfg.gasLimit, cfg.maxGasPrice, cfg.minGasPrice, cfg.gasMultiplier, cfg.egsApiKey, cfg.egsSpeed)
-	err = conn.Connect()
+	erc721HandlerContract, err := erc721Handler.NewERC721Handler(cfg.erc721HandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
-	err = conn.EnsureHasBytecode(cfg.bridgeContract)
+
+	genericHandlerContract, err := GenericHandler.NewGenericHandler(cfg.genericHandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
 
-	if cfg.erc20HandlerContract != utils.ZeroAddress {
-		err = conn.EnsureHasBytecode(cfg.erc20HandlerContract)
+	return &contracts{
+		bridge:         bridgeContract,
+		erc20Handler:   erc20HandlerContract,
+		erc721Handler:  erc721HandlerContract,
+		genericHandler: genericHandlerContract,
+	}, nil
+}
+
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics) (*Chain, error) {
+	cfg, err := parseChainConfig(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+	// Kept before the start block is adjusted, for comparison with reloaded configs
+	config := *cfg
+
+	// With a remote signer the key never leaves the signer, so there is no keypair to load
+	var kp *secp256k1.Keypair
+	var signer connection.Signer
+	if cfg.signer == RemoteSigner {
+		signer, err = connection.NewRemoteSigner(cfg.signerUrl, common.HexToAddress(cfg.from))
 		if err != nil {
 			return nil, err
 		}
-	}
-
-	if cfg.genericHandlerContract != utils.ZeroAddress {
-		err = conn.EnsureHasBytecode(cfg.genericHandlerContract)
+	} else {
+		kpI, err := keystore.KeypairFromAddress(cfg.from, keystore.EthChain, cfg.keystorePath, chainCfg.Insecure)
 		if err != nil {
 			return nil, err
 		}
+		kp, _ = kpI.(*secp256k1.Keypair)
+		signer = connection.NewKeystoreSigner(kp)
 	}
+	relayer := signer.Address().Hex()
 
-	bridgeContract, err := bridge.NewBridge(cfg.bridgeContract, conn.Client())
+	bs, err := setupBlockstore(cfg, relayer)
 	if err != nil {
 		return nil, err
 	}
 
-	chainId, err := bridgeContract.ChainID(conn.CallOpts())
+	queue, err := msgqueue.NewQueue(cfg.blockstorePath, cfg.id, relayer)
 	if err != nil {
 		return nil, err
 	}
 
-	if chainId != uint8(chainCfg.Id) {
-		return nil, fmt.Errorf("chainId (%d) and configuration chainId (%d) do not match", chainId, chainCfg.Id)
-	}
-
-	erc20HandlerContract, err := erc20Handler.NewERC20Handler(cfg.erc20HandlerContract, conn.Client())
+	stop := make(chan int)
+	writerStop := make(chan int)
+	conn := connection.NewConnection(cfg.endpoint, cfg.http, kp, logger, cfg.gasLimit, cfg.maxGasPrice, cfg.minGasPrice, cfg.gasMultiplier)
+	conn.AddEndpoints(cfg.endpoints...)
+	conn.SetSigner(signer)
+	conn.SetGasMargin(cfg.gasMargin)
+	conn.SetGasPriceOracle(newGasPriceOracle(cfg, conn.NodeBackend(), logger))
+	conn.SetTipStrategy(cfg.feeHistoryBlocks, cfg.tipPercentile, cfg.minTip, cfg.maxTip)
+	err = conn.Connect()
 	if err != nil {
 		return nil, err
 	}
-
-	erc721HandlerContract, err := erc721Handler.NewERC721Handler(cfg.erc721HandlerContract, conn.Client())
+	err = checkContracts(conn, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	genericHandlerContract, err := GenericHandler.NewGenericHandler(cfg.genericHandlerContract, conn.Client())
+	contracts, err := bindContracts(conn, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -170,23 +280,57 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 	}
 
 	listener := NewListener(conn, cfg, logger, bs, stop, sysErr, m)
-	listener.setContracts(bridgeContract, erc20HandlerContract, erc721HandlerContract, genericHandlerContract)
+	listener.setContracts(contracts.bridge, contracts.erc20Handler, contracts.erc721Handler, contracts.genericHandler)
+	writer := NewWriter(conn, cfg, logger, writerStop, sysErr, m)
+	if m != nil {
+		relayerMetrics := chains.NewMetrics(chainCfg.Name)
+		listener.setMetrics(relayerMetrics)
+		writer.setMetrics(relayerMetrics)
+	}
+	writer.setContract(contracts.bridge)
+	writer.setQueue(queue)
+	if cfg.voteOnly {
+		// Other relayers execute the proposals, so nothing is left to do once a message is voted on
+		queue.SetVotedFinished()
+	}
 
-	writer := NewWriter(conn, cfg, logger, stop, sysErr, m)
-	writer.setContract(bridgeContract)
+	balance := chains.NewBalanceMonitor(conn.Balance, cfg.balanceThreshold, logger, writerStop)
+	if m != nil {
+		balance.RegisterMetrics(chainCfg.Name)
+	}
 
 	return &Chain{
-		cfg:      chainCfg,
-		conn:     conn,
-		writer:   writer,
-		listener: listener,
-		stop:     stop,
+		cfg:             chainCfg,
+		config:          &config,
+		conn:            conn,
+		writer:          writer,
+		listener:        listener,
+		stop:            stop,
+		writerStop:      writerStop,
+		shutdownTimeout: DefaultShutdownTimeout,
+		balance:         balance,
 	}, nil
 }
 
 func (c *Chain) SetRouter(r *core.Router) {
 	r.Listen(c.cfg.Id, c.writer)
-	c.listener.setRouter(r)
+	var router chains.Router = chains.NewLoggingRouter(r, c.listener.log)
+	if c.gate != nil {
+		c.gate.SetRouter(router)
+		router = c.gate
+	}
+	c.listener.setRouter(router)
+}
+
+// SetTransferGate sets the gate the listener's transfers pass before being routed. It must be called before the
+// chain is added to the core.
+func (c *Chain) SetTransferGate(g *limits.Gate) {
+	c.gate = g
+}
+
+// TransferGate returns the gate the listener's transfers pass, or nil if the chain has no limits
+func (c *Chain) TransferGate() *limits.Gate {
+	return c.gate
 }
 
 func (c *Chain) Start() error {
@@ -199,11 +343,72 @@ func (c *Chain) Start() error {
 	if err != nil {
 		return err
 	}
+	c.balance.Start()
 
 	c.writer.log.Debug("Successfully started chain")
 	return nil
 }
 
+// SetLifecycleMetrics sets the message lifecycle metrics shared by all chains
+func (c *Chain) SetLifecycleMetrics(m *chains.LifecycleMetrics) {
+	c.listener.setLifecycleMetrics(m)
+	c.writer.setLifecycleMetrics(m)
+}
+
+// SetPolicy sets the policy the writer checks messages against before voting
+func (c *Chain) SetPolicy(p *policy.Policy) {
+	c.writer.setPolicy(p)
+}
+
+// DepositVerifier returns the verifier of deposits made on this chain
+func (c *Chain) DepositVerifier() chains.DepositVerifier {
+	return c.listener
+}
+
+// SetVerifier sets the verifier the writer re-checks deposits with before voting, if verifyDeposits is enabled
+func (c *Chain) SetVerifier(v *chains.Verifier) {
+	if c.config.verifyDeposits {
+		c.writer.setVerifier(v)
+	}
+}
+
+// SetReorgedDeposits sets the set shared by all chains, which the listener adds deposits that are no longer on the
+// canonical chain to and the writer checks before voting
+func (c *Chain) SetReorgedDeposits(r *chains.ReorgedDeposits) {
+	c.listener.setReorgedDeposits(r)
+	c.writer.setReorgedDeposits(r)
+}
+
+// BalanceMonitor returns the monitor of the relayer balance
+func (c *Chain) BalanceMonitor() *chains.BalanceMonitor {
+	return c.balance
+}
+
+// ListenerControl returns the control used to pause or re-scan the listener
+func (c *Chain) ListenerControl() *chains.ListenerControl {
+	return &c.listener.control
+}
+
+// MessageQueue returns the queue of messages routed to the writer
+func (c *Chain) MessageQueue() *msgqueue.Queue {
+	return c.writer.queue
+}
+
+// DisableMessageQueue stops the writer from recording messages in the queue, for when another process owns it
+func (c *Chain) DisableMessageQueue() {
+	c.writer.setQueue(nil)
+}
+
+// Resubmit resolves a message in the writer's queue again
+func (c *Chain) Resubmit(m msg.Message) {
+	c.writer.resubmit(m)
+}
+
+// ResolveMessage resolves a message with the chain's writer, returning once the writer is done with it
+func (c *Chain) ResolveMessage(m msg.Message) bool {
+	return c.writer.ResolveMessage(m)
+}
+
 func (c *Chain) Id() msg.ChainId {
 	return c.cfg.Id
 }
@@ -216,9 +421,24 @@ func (c *Chain) LatestBlock() metrics.LatestBlock {
 	return c.listener.latestBlock
 }
 
-// Stop signals to any running routines to exit
+// SetShutdownTimeout sets how long Stop waits for pending votes and executions to complete
+func (c *Chain) SetShutdownTimeout(d time.Duration) {
+	c.shutdownTimeout = d
+}
+
+// StopListener stops the listener so no new deposits are routed. The writer keeps running until Stop.
+func (c *Chain) StopListener() {
+	c.stopListener.Do(func() {
+		close(c.stop)
+	})
+}
+
+// Stop signals to any running routines to exit. The listener is stopped first so no new deposits are routed, then
+// the writer is given up to the shutdown timeout to finish pending votes and executions.
 func (c *Chain) Stop() {
-	close(c.stop)
+	c.StopListener()
+	c.writer.drainer.Drain(c.shutdownTimeout)
+	close(c.writerStop)
 	if c.conn != nil {
 		c.conn.Close()
 	}
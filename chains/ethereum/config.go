@@ -8,10 +8,15 @@ import (
 	"fmt"
 	"github.com/UltronFoundationDev/chainbridge-utils/core"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/egs"
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const DefaultGasLimit = 6721975
@@ -20,9 +25,20 @@ const DefaultMinGasPrice = 0
 const DefaultBlockConfirmations = 10
 const DefaultBlockSuccessRetryInterval = 400
 const DefaultGasMultiplier = 1
+const DefaultGasMargin = 20
+const DefaultBlockRange = 500
+const DefaultTxTimeout = 120
+const DefaultReceiptTimeout = 600
+
+// Signer backends
+const (
+	KeystoreSigner = "keystore"
+	RemoteSigner   = "remote"
+)
 
 // Chain specific options
 var (
+	EndpointsOpt                 = "endpoints"
 	BridgeOpt                    = "bridge"
 	Erc20HandlerOpt              = "erc20Handler"
 	Erc721HandlerOpt             = "erc721Handler"
@@ -30,13 +46,34 @@ var (
 	MaxGasPriceOpt               = "maxGasPrice"
 	MinGasPriceOpt               = "minGasPrice"
 	GasLimitOpt                  = "gasLimit"
+	GasMarginOpt                 = "gasMargin"
 	GasMultiplier                = "gasMultiplier"
 	HttpOpt                      = "http"
 	StartBlockOpt                = "startBlock"
 	BlockConfirmationsOpt        = "blockConfirmations"
 	BlockSuccessRetryIntervalOpt = "blockSuccessRetryInterval"
+	BlockRangeOpt                = "blockRange"
+	TxTimeoutOpt                 = "txTimeout"
+	ReceiptTimeoutOpt            = "receiptTimeout"
+	VoteOnlyOpt                  = "voteOnly"
+	SignerOpt                    = "signer"
+	SignerUrlOpt                 = "signerUrl"
 	EGSApiKey                    = "egsApiKey"
 	EGSSpeed                     = "egsSpeed"
+	GasPriceOraclesOpt           = "gasPriceOracles"
+	GasPricePercentileOpt        = "gasPricePercentile"
+	GasPriceOracleUrlOpt         = "gasPriceOracleUrl"
+	GasPriceOracleJsonPathOpt    = "gasPriceOracleJsonPath"
+	GasPriceOracleUnitOpt        = "gasPriceOracleUnit"
+	FixedGasPriceOpt             = "fixedGasPrice"
+	FeeHistoryBlocksOpt          = "feeHistoryBlocks"
+	TipPercentileOpt             = "tipPercentile"
+	MinTipOpt                    = "minTip"
+	MaxTipOpt                    = "maxTip"
+	BalanceThresholdOpt          = "balanceThreshold"
+	DustPolicyOpt                = "dustPolicy"
+	VerifyDepositsOpt            = "verifyDeposits"
+	VerifyConfirmationsOpt       = "verifyConfirmations"
 )
 
 // Config encapsulates all necessary parameters in ethereum compatible forms
@@ -44,6 +81,7 @@ type Config struct {
 	name                      string      // Human-readable chain name
 	id                        msg.ChainId // ChainID
 	endpoint                  string      // url for rpc endpoint
+	endpoints                 []string    // urls for additional rpc endpoints to fail over to
 	from                      string      // address of key to use
 	keystorePath              string      // Location of keyfiles
 	blockstorePath            string
@@ -52,7 +90,8 @@ type Config struct {
 	erc20HandlerContract      common.Address
 	erc721HandlerContract     common.Address
 	genericHandlerContract    common.Address
-	gasLimit                  *big.Int
+	gasLimit                  *big.Int // Ceiling for gas estimates, used when estimation fails
+	gasMargin                 uint64   // Percentage added to gas estimates
 	maxGasPrice               *big.Int
 	minGasPrice               *big.Int
 	gasMultiplier             *big.Float
@@ -60,9 +99,29 @@ type Config struct {
 	startBlock                *big.Int
 	blockConfirmations        *big.Int
 	blockSuccessRetryInterval *big.Int
+	blockRange                *big.Int      // Maximum number of blocks queried per FilterLogs call
+	txTimeout                 time.Duration // Time a transaction may stay pending before it is replaced with higher fees
+	receiptTimeout            time.Duration // Time to wait for a submitted transaction to be mined before retrying
+	voteOnly                  bool          // Only vote on proposals, leaving execution to other parties
+	signer                    string        // Backend that signs transactions: keystore or remote
+	signerUrl                 string        // url of the remote signer
 	decimals                  map[msg.ChainId]map[string][2]uint8
-	egsApiKey                 string // API key for ethgasstation to query gas prices
-	egsSpeed                  string // The speed which a transaction should be processed: average, fast, fastest. Default: fast
+	dustPolicy                amount.DustPolicy
+	egsApiKey                 string   // API key for ethgasstation to query gas prices
+	egsSpeed                  string   // The speed which a transaction should be processed: average, fast, fastest. Default: fast
+	gasPriceOracles           []string // Oracles whose median is the suggested gas price
+	gasPricePercentile        float64  // Fee history percentile used by the node oracle, 0 uses eth_gasPrice
+	gasPriceOracleUrl         string   // url of the http oracle
+	gasPriceOracleJsonPath    string   // Path of the price in the response of the http oracle
+	gasPriceOracleUnit        *big.Int // Wei per unit of the price returned by the http oracle
+	fixedGasPrice             *big.Int // Price returned by the fixed oracle
+	feeHistoryBlocks          uint64   // Number of recent blocks fee percentiles are taken from
+	tipPercentile             float64  // Fee history percentile used for the priority fee, 0 uses eth_maxPriorityFeePerGas
+	minTip                    *big.Int // Lower bound of the priority fee
+	maxTip                    *big.Int // Upper bound of the priority fee
+	balanceThreshold          *big.Int // Relayer balance below which a warning is logged and health is degraded
+	verifyDeposits            bool     // Re-check deposits on the source chain before voting
+	verifyConfirmations       *big.Int // Confirmations deposits on this chain need to be verified, nil uses blockConfirmations
 }
 
 // parseChainConfig uses a core.ChainConfig to construct a corresponding Config
@@ -72,6 +131,7 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		name:                      chainCfg.Name,
 		id:                        chainCfg.Id,
 		endpoint:                  chainCfg.Endpoint,
+		endpoints:                 nil,
 		from:                      chainCfg.From,
 		keystorePath:              chainCfg.KeystorePath,
 		blockstorePath:            chainCfg.BlockstorePath,
@@ -81,6 +141,7 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		erc721HandlerContract:     utils.ZeroAddress,
 		genericHandlerContract:    utils.ZeroAddress,
 		gasLimit:                  big.NewInt(DefaultGasLimit),
+		gasMargin:                 DefaultGasMargin,
 		maxGasPrice:               big.NewInt(DefaultGasPrice),
 		minGasPrice:               big.NewInt(DefaultMinGasPrice),
 		gasMultiplier:             big.NewFloat(DefaultGasMultiplier),
@@ -88,9 +149,38 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		startBlock:                big.NewInt(0),
 		blockConfirmations:        big.NewInt(0),
 		blockSuccessRetryInterval: big.NewInt(0),
+		blockRange:                big.NewInt(DefaultBlockRange),
+		txTimeout:                 time.Second * DefaultTxTimeout,
+		receiptTimeout:            time.Second * DefaultReceiptTimeout,
+		voteOnly:                  false,
+		signer:                    KeystoreSigner,
+		signerUrl:                 "",
 		decimals:                  chainCfg.Decimals,
+		dustPolicy:                amount.DefaultDustPolicy,
 		egsApiKey:                 "",
 		egsSpeed:                  "",
+		gasPriceOracles:           []string{oracle.NodeOracle},
+		gasPricePercentile:        0,
+		gasPriceOracleUrl:         "",
+		gasPriceOracleJsonPath:    "",
+		gasPriceOracleUnit:        big.NewInt(1),
+		fixedGasPrice:             nil,
+		feeHistoryBlocks:          oracle.DefaultFeeHistoryBlocks,
+		tipPercentile:             0,
+		minTip:                    nil,
+		maxTip:                    nil,
+		balanceThreshold:          nil,
+		verifyDeposits:            false,
+		verifyConfirmations:       nil,
+	}
+
+	if endpoints, ok := chainCfg.Opts[EndpointsOpt]; ok {
+		for _, e := range strings.Split(endpoints, ",") {
+			if e = strings.TrimSpace(e); e != "" && e != config.endpoint {
+				config.endpoints = append(config.endpoints, e)
+			}
+		}
+		delete(chainCfg.Opts, EndpointsOpt)
 	}
 
 	if contract, ok := chainCfg.Opts[BridgeOpt]; ok && contract != "" {
@@ -145,6 +235,15 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		delete(chainCfg.Opts, GasLimitOpt)
 	}
 
+	if gasMargin, ok := chainCfg.Opts[GasMarginOpt]; ok && gasMargin != "" {
+		val, err := strconv.ParseUint(gasMargin, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s", GasMarginOpt)
+		}
+		config.gasMargin = val
+	}
+	delete(chainCfg.Opts, GasMarginOpt)
+
 	if gasMultiplier, ok := chainCfg.Opts[GasMultiplier]; ok {
 		multilier := big.NewFloat(1)
 		_, pass := multilier.SetString(gasMultiplier)
@@ -203,6 +302,68 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		delete(chainCfg.Opts, BlockSuccessRetryIntervalOpt)
 	}
 
+	if txTimeout, ok := chainCfg.Opts[TxTimeoutOpt]; ok && txTimeout != "" {
+		val, err := strconv.ParseUint(txTimeout, 10, 32)
+		if err != nil || val == 0 {
+			return nil, fmt.Errorf("unable to parse %s", TxTimeoutOpt)
+		}
+		config.txTimeout = time.Second * time.Duration(val)
+	}
+	delete(chainCfg.Opts, TxTimeoutOpt)
+
+	if receiptTimeout, ok := chainCfg.Opts[ReceiptTimeoutOpt]; ok && receiptTimeout != "" {
+		val, err := strconv.ParseUint(receiptTimeout, 10, 32)
+		if err != nil || val == 0 {
+			return nil, fmt.Errorf("unable to parse %s", ReceiptTimeoutOpt)
+		}
+		config.receiptTimeout = time.Second * time.Duration(val)
+	}
+	delete(chainCfg.Opts, ReceiptTimeoutOpt)
+
+	if blockRange, ok := chainCfg.Opts[BlockRangeOpt]; ok && blockRange != "" {
+		val := big.NewInt(DefaultBlockRange)
+		_, pass := val.SetString(blockRange, 10)
+		if pass && val.Sign() == 1 {
+			config.blockRange = val
+			delete(chainCfg.Opts, BlockRangeOpt)
+		} else {
+			return nil, fmt.Errorf("unable to parse %s", BlockRangeOpt)
+		}
+	} else {
+		delete(chainCfg.Opts, BlockRangeOpt)
+	}
+
+	if voteOnly, ok := chainCfg.Opts[VoteOnlyOpt]; ok && voteOnly != "" {
+		val, parseErr := strconv.ParseBool(voteOnly)
+		if parseErr != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", VoteOnlyOpt, parseErr)
+		}
+		config.voteOnly = val
+	}
+	delete(chainCfg.Opts, VoteOnlyOpt)
+
+	if signer, ok := chainCfg.Opts[SignerOpt]; ok && signer != "" {
+		if signer != KeystoreSigner && signer != RemoteSigner {
+			return nil, fmt.Errorf("unknown %s %q, must be %s or %s", SignerOpt, signer, KeystoreSigner, RemoteSigner)
+		}
+		config.signer = signer
+		delete(chainCfg.Opts, SignerOpt)
+	}
+
+	if url, ok := chainCfg.Opts[SignerUrlOpt]; ok && url != "" {
+		config.signerUrl = url
+		delete(chainCfg.Opts, SignerUrlOpt)
+	}
+
+	if config.signer == RemoteSigner {
+		if config.signerUrl == "" {
+			return nil, fmt.Errorf("must provide opts.%s field for remote signer", SignerUrlOpt)
+		}
+		if !common.IsHexAddress(config.from) {
+			return nil, fmt.Errorf("from must be the address of the remote signer account, got %q", config.from)
+		}
+	}
+
 	if gsnApiKey, ok := chainCfg.Opts[EGSApiKey]; ok && gsnApiKey != "" {
 		config.egsApiKey = gsnApiKey
 		delete(chainCfg.Opts, EGSApiKey)
@@ -217,9 +378,160 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		delete(chainCfg.Opts, EGSSpeed)
 	}
 
+	if threshold, ok := chainCfg.Opts[BalanceThresholdOpt]; ok && threshold != "" {
+		val, parseErr := utils.ParseUint256OrHex(&threshold)
+		if parseErr != nil {
+			return nil, fmt.Errorf("unable to parse balance threshold, %w", parseErr)
+		}
+		config.balanceThreshold = val
+	}
+	delete(chainCfg.Opts, BalanceThresholdOpt)
+
+	if policy, ok := chainCfg.Opts[DustPolicyOpt]; ok && policy != "" {
+		val, parseErr := amount.ParseDustPolicy(policy)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		config.dustPolicy = val
+	}
+	delete(chainCfg.Opts, DustPolicyOpt)
+
+	if verify, ok := chainCfg.Opts[VerifyDepositsOpt]; ok && verify != "" {
+		val, parseErr := strconv.ParseBool(verify)
+		if parseErr != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", VerifyDepositsOpt, parseErr)
+		}
+		config.verifyDeposits = val
+	}
+	delete(chainCfg.Opts, VerifyDepositsOpt)
+
+	if confirmations, ok := chainCfg.Opts[VerifyConfirmationsOpt]; ok && confirmations != "" {
+		val, pass := big.NewInt(0).SetString(confirmations, 10)
+		if !pass || val.Sign() < 0 {
+			return nil, fmt.Errorf("unable to parse %s", VerifyConfirmationsOpt)
+		}
+		config.verifyConfirmations = val
+	}
+	delete(chainCfg.Opts, VerifyConfirmationsOpt)
+
+	err := parseGasPriceOracles(chainCfg, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseTipStrategy(chainCfg, config)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(chainCfg.Opts) != 0 {
 		return nil, fmt.Errorf("unknown Opts Encountered: %#v", chainCfg.Opts)
 	}
 
 	return config, nil
 }
+
+// parseGasPriceOracles parses the selected gas price oracles and their options
+func parseGasPriceOracles(chainCfg *core.ChainConfig, config *Config) error {
+	if oracles, ok := chainCfg.Opts[GasPriceOraclesOpt]; ok && oracles != "" {
+		config.gasPriceOracles = nil
+		for _, name := range strings.Split(oracles, ",") {
+			name = strings.TrimSpace(name)
+			switch name {
+			case oracle.NodeOracle, oracle.HTTPOracle, oracle.FixedOracle, oracle.EGSOracle:
+			default:
+				return fmt.Errorf("unknown gas price oracle %q", name)
+			}
+			config.gasPriceOracles = append(config.gasPriceOracles, name)
+		}
+	}
+	delete(chainCfg.Opts, GasPriceOraclesOpt)
+
+	if percentile, ok := chainCfg.Opts[GasPricePercentileOpt]; ok && percentile != "" {
+		val, err := strconv.ParseFloat(percentile, 64)
+		if err != nil || val < 0 || val > 100 {
+			return fmt.Errorf("unable to parse %s, must be between 0 and 100", GasPricePercentileOpt)
+		}
+		config.gasPricePercentile = val
+	}
+	delete(chainCfg.Opts, GasPricePercentileOpt)
+
+	config.gasPriceOracleUrl = chainCfg.Opts[GasPriceOracleUrlOpt]
+	delete(chainCfg.Opts, GasPriceOracleUrlOpt)
+	config.gasPriceOracleJsonPath = chainCfg.Opts[GasPriceOracleJsonPathOpt]
+	delete(chainCfg.Opts, GasPriceOracleJsonPathOpt)
+
+	if unit, ok := chainCfg.Opts[GasPriceOracleUnitOpt]; ok && unit != "" {
+		val, err := oracle.ParseUnit(unit)
+		if err != nil {
+			return err
+		}
+		config.gasPriceOracleUnit = val
+	}
+	delete(chainCfg.Opts, GasPriceOracleUnitOpt)
+
+	if fixedGasPrice, ok := chainCfg.Opts[FixedGasPriceOpt]; ok && fixedGasPrice != "" {
+		price, parseErr := utils.ParseUint256OrHex(&fixedGasPrice)
+		if parseErr != nil {
+			return fmt.Errorf("unable to parse fixed gas price, %w", parseErr)
+		}
+		config.fixedGasPrice = price
+	}
+	delete(chainCfg.Opts, FixedGasPriceOpt)
+
+	for _, name := range config.gasPriceOracles {
+		switch {
+		case name == oracle.HTTPOracle && (config.gasPriceOracleUrl == "" || config.gasPriceOracleJsonPath == ""):
+			return fmt.Errorf("must provide opts.%s and opts.%s for the http gas price oracle", GasPriceOracleUrlOpt, GasPriceOracleJsonPathOpt)
+		case name == oracle.FixedOracle && config.fixedGasPrice == nil:
+			return fmt.Errorf("must provide opts.%s for the fixed gas price oracle", FixedGasPriceOpt)
+		case name == oracle.EGSOracle && config.egsApiKey == "":
+			return fmt.Errorf("must provide opts.%s for the egs gas price oracle", EGSApiKey)
+		}
+	}
+	return nil
+}
+
+// parseTipStrategy parses the options of the priority fee of dynamic fee transactions
+func parseTipStrategy(chainCfg *core.ChainConfig, config *Config) error {
+	if blocks, ok := chainCfg.Opts[FeeHistoryBlocksOpt]; ok && blocks != "" {
+		val, err := strconv.ParseUint(blocks, 10, 32)
+		if err != nil || val == 0 {
+			return fmt.Errorf("unable to parse %s", FeeHistoryBlocksOpt)
+		}
+		config.feeHistoryBlocks = val
+	}
+	delete(chainCfg.Opts, FeeHistoryBlocksOpt)
+
+	if percentile, ok := chainCfg.Opts[TipPercentileOpt]; ok && percentile != "" {
+		val, err := strconv.ParseFloat(percentile, 64)
+		if err != nil || val < 0 || val > 100 {
+			return fmt.Errorf("unable to parse %s, must be between 0 and 100", TipPercentileOpt)
+		}
+		config.tipPercentile = val
+	}
+	delete(chainCfg.Opts, TipPercentileOpt)
+
+	if minTip, ok := chainCfg.Opts[MinTipOpt]; ok && minTip != "" {
+		tip, parseErr := utils.ParseUint256OrHex(&minTip)
+		if parseErr != nil {
+			return fmt.Errorf("unable to parse min tip, %w", parseErr)
+		}
+		config.minTip = tip
+	}
+	delete(chainCfg.Opts, MinTipOpt)
+
+	if maxTip, ok := chainCfg.Opts[MaxTipOpt]; ok && maxTip != "" {
+		tip, parseErr := utils.ParseUint256OrHex(&maxTip)
+		if parseErr != nil {
+			return fmt.Errorf("unable to parse max tip, %w", parseErr)
+		}
+		config.maxTip = tip
+	}
+	delete(chainCfg.Opts, MaxTipOpt)
+
+	if config.minTip != nil && config.maxTip != nil && config.minTip.Cmp(config.maxTip) == 1 {
+		return fmt.Errorf("%s must not exceed %s", MinTipOpt, MaxTipOpt)
+	}
+	return nil
+}
@@ -6,9 +6,13 @@ package ethereum
 import (
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -57,14 +61,22 @@ func TestParseChainConfig(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               big.NewInt(10),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(20),
 		minGasPrice:            big.NewInt(0),
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(50),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 		egsSpeed:               "fast",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -121,14 +133,22 @@ func TestParseChainConfigHex(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               expectedHex,
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            expectedHex,
 		minGasPrice:            expectedHex,
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(50),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 		egsSpeed:               "fast",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -177,14 +197,22 @@ func TestParseChainConfigWithNoBlockConfirmations(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               big.NewInt(10),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(20),
 		minGasPrice:            big.NewInt(0),
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(DefaultBlockConfirmations),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "",
 		egsSpeed:               "fast",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -229,14 +257,22 @@ func TestChainConfigOneContract(t *testing.T) {
 		bridgeContract:       common.HexToAddress("0x1234"),
 		erc20HandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:             big.NewInt(10),
+		gasMargin:            DefaultGasMargin,
 		maxGasPrice:          big.NewInt(20),
 		minGasPrice:          big.NewInt(0),
 		gasMultiplier:        big.NewFloat(1),
 		http:                 true,
 		startBlock:           big.NewInt(10),
 		blockConfirmations:   big.NewInt(DefaultBlockConfirmations),
+		blockRange:           big.NewInt(DefaultBlockRange),
+		txTimeout:            time.Second * DefaultTxTimeout,
+		receiptTimeout:       time.Second * DefaultReceiptTimeout,
 		egsApiKey:            "",
 		egsSpeed:             "fast",
+		gasPriceOracles:      []string{oracle.NodeOracle},
+		gasPriceOracleUnit:   big.NewInt(1),
+		feeHistoryBlocks:     oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:           amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -345,14 +381,22 @@ func TestEthGasStationDefaultSpeed(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               big.NewInt(10),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(20),
 		minGasPrice:            big.NewInt(0),
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(DefaultBlockConfirmations),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 		egsSpeed:               "fast",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -401,14 +445,22 @@ func TestEthGasStationCustomSpeed(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               big.NewInt(10),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(20),
 		minGasPrice:            big.NewInt(0),
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(DefaultBlockConfirmations),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 		egsSpeed:               "average",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -457,17 +509,147 @@ func TestEthGasStationInvalidSpeed(t *testing.T) {
 		erc721HandlerContract:  common.HexToAddress("0x1234"),
 		genericHandlerContract: common.HexToAddress("0x1234"),
 		gasLimit:               big.NewInt(10),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(20),
 		minGasPrice:            big.NewInt(0),
 		gasMultiplier:          big.NewFloat(1),
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(DefaultBlockConfirmations),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 		egsApiKey:              "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
 		egsSpeed:               "fast",
+		gasPriceOracles:        []string{oracle.NodeOracle},
+		gasPriceOracleUnit:     big.NewInt(1),
+		feeHistoryBlocks:       oracle.DefaultFeeHistoryBlocks,
+		dustPolicy:             amount.DefaultDustPolicy,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
 		t.Fatalf("Output not expected.\n\tExpected: %#v\n\tGot: %#v\n", &expected, out)
 	}
 }
+
+func TestParseChainConfigVoteOnly(t *testing.T) {
+	input := core.ChainConfig{
+		Name:         "chain",
+		Id:           1,
+		Endpoint:     "endpoint",
+		From:         "0x0",
+		KeystorePath: "./keys",
+		Insecure:     false,
+		Opts: map[string]string{
+			"bridge":   "0x1234",
+			"voteOnly": "true",
+		},
+	}
+
+	out, err := parseChainConfig(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.voteOnly {
+		t.Fatal("expected voteOnly to be set")
+	}
+
+	input.Opts = map[string]string{
+		"bridge":   "0x1234",
+		"voteOnly": "yes",
+	}
+	_, err = parseChainConfig(&input)
+	if err == nil || !strings.Contains(err.Error(), "voteOnly") {
+		t.Fatalf("expected invalid voteOnly value to fail, got %v", err)
+	}
+}
+
+func TestParseChainConfigEndpoints(t *testing.T) {
+	input := core.ChainConfig{
+		Name:         "chain",
+		Id:           1,
+		Endpoint:     "ws://a",
+		From:         "0x0",
+		KeystorePath: "./keys",
+		Insecure:     false,
+		Opts: map[string]string{
+			"bridge":    "0x1234",
+			"endpoints": "ws://a, ws://b,,ws://c",
+		},
+	}
+
+	out, err := parseChainConfig(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out.endpoints, []string{"ws://b", "ws://c"}) {
+		t.Fatalf("unexpected endpoints: %v", out.endpoints)
+	}
+}
+
+func TestParseChainConfigRemoteSigner(t *testing.T) {
+	opts := func() map[string]string {
+		return map[string]string{
+			"bridge":    "0x1234",
+			"signer":    "remote",
+			"signerUrl": "http://localhost:8550",
+		}
+	}
+	input := core.ChainConfig{
+		Name:     "chain",
+		Id:       1,
+		Endpoint: "endpoint",
+		From:     "0xff93B45308FD417dF303D6515aB04D9e89a750Ca",
+		Opts:     opts(),
+	}
+
+	out, err := parseChainConfig(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.signer != RemoteSigner || out.signerUrl != "http://localhost:8550" {
+		t.Fatalf("unexpected signer config: %s %s", out.signer, out.signerUrl)
+	}
+
+	input.Opts = opts()
+	delete(input.Opts, "signerUrl")
+	if _, err = parseChainConfig(&input); err == nil {
+		t.Fatal("expected missing signerUrl to fail")
+	}
+
+	input.Opts = opts()
+	input.Opts["signer"] = "hsm"
+	if _, err = parseChainConfig(&input); err == nil {
+		t.Fatal("expected unknown signer to fail")
+	}
+}
+
+func TestParseChainConfigVerifyDeposits(t *testing.T) {
+	input := core.ChainConfig{
+		Name:     "chain",
+		Id:       1,
+		Endpoint: "endpoint",
+		From:     "0x0",
+		Opts: map[string]string{
+			"bridge":              "0x1234",
+			"verifyDeposits":      "true",
+			"verifyConfirmations": "20",
+		},
+	}
+
+	out, err := parseChainConfig(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.verifyDeposits || out.verifyConfirmations.Cmp(big.NewInt(20)) != 0 {
+		t.Fatalf("unexpected verification config: %t %s", out.verifyDeposits, out.verifyConfirmations)
+	}
+
+	input.Opts = map[string]string{
+		"bridge":              "0x1234",
+		"verifyConfirmations": "-1",
+	}
+	if _, err = parseChainConfig(&input); err == nil {
+		t.Fatal("expected negative verifyConfirmations to fail")
+	}
+}
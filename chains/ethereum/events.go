@@ -6,14 +6,12 @@ package ethereum
 import (
 	"errors"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"math"
 	"math/big"
-	"strings"
 )
 
 func (l *listener) handleErc20DepositedEvent(destId msg.ChainId, nonce msg.Nonce, resourceID msg.ResourceId, callData []byte) (msg.Message, error) {
-	l.log.Info("Handling fungible deposit event", "dest", destId, "nonce", nonce, "rID", resourceID.Hex())
+	log := l.depositLogger(destId, nonce)
+	log.Info("Handling fungible deposit event", "dest", destId, "nonce", nonce, "rID", resourceID.Hex())
 
 	if len(callData) < 84 {
 		err := errors.New("invalid calldata length: less than 84 bytes")
@@ -29,46 +27,34 @@ func (l *listener) handleErc20DepositedEvent(destId msg.ChainId, nonce msg.Nonce
 	// amount: first 32 bytes of calldata
 	amount := big.NewInt(0).SetBytes(callData[:32])
 
-	// change amount if difference decimals in destId
-	if len(l.cfg.decimals) != 0 {
-		for chainId, resourceIdDecimals := range l.cfg.decimals {
-			if chainId == destId {
-				for decimalResourceID, decimalsLst := range resourceIdDecimals {
-					if strings.HasPrefix(decimalResourceID, "0x") {
-						decimalResourceID = decimalResourceID[2:]
-					}
-					if decimalResourceID == resourceID.Hex() {
-						if decimalsLst[0] > decimalsLst[1] {
-							differenceDecimals := big.NewInt(int64(math.Pow(10, float64(decimalsLst[0]-decimalsLst[1]))))
-							amount = big.NewInt(0).Div(amount, differenceDecimals)
-						} else {
-							differenceDecimals := big.NewInt(int64(math.Pow(10, float64(decimalsLst[1]-decimalsLst[0]))))
-							amount = big.NewInt(0).Mul(amount, differenceDecimals)
-						}
-						break
-					}
-				}
-				break
-			}
-		}
+	// convert the amount to the decimals of the token on destId
+	conv, err := l.amounts.Convert(destId, resourceID, amount)
+	if err != nil {
+		return msg.Message{}, err
+	}
+	if conv.Refund {
+		log.Warn("Dust flagged for refund", "dust", conv.Dust, "amount", amount, "rID", resourceID.Hex())
+	} else if conv.Dust.Sign() != 0 {
+		log.Info("Dropped dust", "dust", conv.Dust, "amount", amount, "rID", resourceID.Hex())
 	}
 
 	return msg.NewFungibleTransfer(
 		l.cfg.id,
 		destId,
 		nonce,
-		amount,
+		conv.Amount,
 		resourceID,
 		recipientAddress,
 	), nil
 }
 
 func (l *listener) handleErc721DepositedEvent(destId msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
-	l.log.Info("Handling nonfungible deposit event")
+	log := l.depositLogger(destId, nonce)
+	log.Info("Handling nonfungible deposit event")
 
-	record, err := l.erc721HandlerContract.GetDepositRecord(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, uint64(nonce), uint8(destId))
+	record, err := l.erc721HandlerContract.GetDepositRecord(l.conn.CallOpts(), uint64(nonce), uint8(destId))
 	if err != nil {
-		l.log.Error("Error Unpacking ERC721 Deposit Record", "err", err)
+		log.Error("Error Unpacking ERC721 Deposit Record", "err", err)
 		return msg.Message{}, err
 	}
 
@@ -84,11 +70,12 @@ func (l *listener) handleErc721DepositedEvent(destId msg.ChainId, nonce msg.Nonc
 }
 
 func (l *listener) handleGenericDepositedEvent(destId msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
-	l.log.Info("Handling generic deposit event")
+	log := l.depositLogger(destId, nonce)
+	log.Info("Handling generic deposit event")
 
-	record, err := l.genericHandlerContract.GetDepositRecord(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, uint64(nonce), uint8(destId))
+	record, err := l.genericHandlerContract.GetDepositRecord(l.conn.CallOpts(), uint64(nonce), uint8(destId))
 	if err != nil {
-		l.log.Error("Error Unpacking Generic Deposit Record", "err", err)
+		log.Error("Error Unpacking Generic Deposit Record", "err", err)
 		return msg.Message{}, nil
 	}
 
@@ -8,9 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/rs/zerolog/log"
 	"math/big"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ChainSafe/log15"
@@ -22,16 +23,18 @@ import (
 	"github.com/UltronFoundationDev/chainbridge/bindings/ERC721Handler"
 	"github.com/UltronFoundationDev/chainbridge/bindings/GenericHandler"
 	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 	eth "github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 var BlockRetryInterval = time.Second * 5
 
 var BlockRetryLimit = 5
 var ErrFatalPolling = errors.New("listener block polling failed")
+var ErrBlockRangeTooLarge = errors.New("block range too large")
 
 type listener struct {
 	cfg                    Config
@@ -47,7 +50,16 @@ type listener struct {
 	sysErr                 chan<- error // Reports fatal error to core
 	latestBlock            metrics.LatestBlock
 	metrics                *metrics.ChainMetrics
+	relayerMetrics         *chains.Metrics
+	lifecycle              *chains.LifecycleMetrics
 	blockConfirmations     *big.Int
+	confirmationsLock      sync.RWMutex
+	control                chains.ListenerControl  // Pause and re-scan requests from the admin API
+	processed              *blockRing              // Recently processed ranges, used to detect reorganizations
+	reorged                map[string]msg.Message  // Routed deposits from blocks that were reorganized, awaiting re-scan
+	rescanEnd              *big.Int                // Last block processed before the most recent reorganization
+	flagged                *chains.ReorgedDeposits // Routed deposits no longer on the canonical chain, shared with writers
+	amounts                *amount.Converter       // Converts fungible amounts to the decimals of the destination
 }
 
 type DepositLogs struct {
@@ -79,6 +91,9 @@ func NewListener(conn Connection, cfg *Config, log log15.Logger, bs blockstore.B
 		latestBlock:        metrics.LatestBlock{LastUpdated: time.Now()},
 		metrics:            m,
 		blockConfirmations: cfg.blockConfirmations,
+		processed:          newBlockRing(ReorgHistory),
+		reorged:            make(map[string]msg.Message),
+		amounts:            amount.NewConverter(cfg.decimals, cfg.dustPolicy),
 	}
 }
 
@@ -95,6 +110,34 @@ func (l *listener) setRouter(r chains.Router) {
 	l.router = r
 }
 
+// setBlockConfirmations changes the number of confirmations required before a block is processed
+func (l *listener) setBlockConfirmations(n *big.Int) {
+	l.confirmationsLock.Lock()
+	defer l.confirmationsLock.Unlock()
+	l.blockConfirmations = n
+}
+
+func (l *listener) confirmations() *big.Int {
+	l.confirmationsLock.RLock()
+	defer l.confirmationsLock.RUnlock()
+	return l.blockConfirmations
+}
+
+// setMetrics sets the relayer metrics, which are optional
+func (l *listener) setMetrics(m *chains.Metrics) {
+	l.relayerMetrics = m
+}
+
+// setReorgedDeposits sets the set routed deposits that are no longer on the canonical chain are added to
+func (l *listener) setReorgedDeposits(r *chains.ReorgedDeposits) {
+	l.flagged = r
+}
+
+// setLifecycleMetrics sets the message lifecycle metrics, which are optional
+func (l *listener) setLifecycleMetrics(m *chains.LifecycleMetrics) {
+	l.lifecycle = m
+}
+
 // start registers all subscriptions provided by the config
 func (l *listener) start() error {
 	l.log.Debug("Starting listener...")
@@ -110,18 +153,38 @@ func (l *listener) start() error {
 }
 
 // pollBlocks will poll for the latest block and proceed to parse the associated events as it sees new blocks.
-// Polling begins at the block defined in `l.cfg.startBlock`. Failed attempts to fetch the latest block or parse
-// a block will be retried up to BlockRetryLimit times before continuing to the next block.
+// Polling begins at the block defined in `l.cfg.startBlock`. Blocks are queried in ranges of up to
+// `l.cfg.blockRange` confirmed blocks at a time. If the node rejects a range as too large the range is halved
+// and the query retried, after each successful query it is doubled again up to `l.cfg.blockRange`. Failed
+// attempts to fetch the latest block or parse a range will be retried up to BlockRetryLimit times before
+// continuing to the next block.
+// Before each range is processed its parent hash is compared with the last processed block. On a mismatch polling
+// resumes from the last block that is still canonical, see handleReorg. A range is only processed if the hash of its
+// end block is unchanged after its logs are fetched.
 func (l *listener) pollBlocks() error {
 	var currentBlock = l.cfg.startBlock
 	l.log.Info("Polling Blocks...", "block", currentBlock)
 	var blockSuccessRetryInterval = time.Millisecond * time.Duration(l.cfg.blockSuccessRetryInterval.Int64())
+	var blockRange = new(big.Int).Set(l.cfg.blockRange)
 	var retry = BlockRetryLimit
 	for {
 		select {
 		case <-l.stop:
 			return errors.New("polling terminated")
 		default:
+			if block := l.control.TakeRescan(); block != nil {
+				l.log.Info("Re-scanning from block", "block", block, "current", currentBlock)
+				currentBlock = block
+				// The processed ranges no longer precede the current block
+				l.processed = newBlockRing(ReorgHistory)
+				retry = BlockRetryLimit
+			}
+			l.control.SetPosition(currentBlock)
+			if l.control.Paused() {
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+
 			// No more retries, goto next block
 			if retry == 0 {
 				l.log.Error("Polling failed, retries exceeded")
@@ -142,28 +205,88 @@ func (l *listener) pollBlocks() error {
 			}
 
 			// Sleep if the difference is less than BlockDelay; (latest - current) < BlockDelay
-			if big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(l.blockConfirmations) == -1 {
+			blockConfirmations := l.confirmations()
+			if big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(blockConfirmations) == -1 {
 				l.log.Debug("Block not ready, will retry", "target", currentBlock, "latest", latestBlock)
 				time.Sleep(BlockRetryInterval)
 				continue
 			}
 
+			endBlock := rangeEnd(currentBlock, latestBlock, blockConfirmations, blockRange)
+
+			// Make sure the blocks processed so far are still canonical
+			rescanFrom, err := l.checkReorg(currentBlock)
+			if err != nil {
+				l.log.Error("Unable to check for chain reorganization", "block", currentBlock, "err", err)
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			} else if rescanFrom != nil {
+				currentBlock = rescanFrom
+				continue
+			}
+
+			endHash, err := l.blockHash(endBlock)
+			if err != nil {
+				l.log.Error("Unable to get block hash", "block", endBlock, "err", err)
+				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+
 			// Parse out events
-			err = l.getDepositEventsForBlock(currentBlock)
+			logs, err := l.fetchDepositLogs(currentBlock, endBlock)
+			if errors.Is(err, ErrBlockRangeTooLarge) && blockRange.Cmp(big.NewInt(1)) == 1 {
+				blockRange.Rsh(blockRange, 1)
+				l.log.Warn("Block range rejected by node, reducing range", "from", currentBlock, "to", endBlock, "range", blockRange, "err", err)
+				continue
+			} else if err != nil {
+				l.log.Error("Failed to get events for block range", "from", currentBlock, "to", endBlock, "err", err)
+				retry--
+				continue
+			}
+			// Grow a reduced range back towards the configured one, a node may only have rejected a busy range
+			if blockRange.Cmp(l.cfg.blockRange) == -1 {
+				blockRange.Lsh(blockRange, 1)
+				if blockRange.Cmp(l.cfg.blockRange) == 1 {
+					blockRange.Set(l.cfg.blockRange)
+				}
+			}
+
+			// The logs must be from the same chain as the hash, or deposits of a reorganized block may be routed
+			hash, err := l.blockHash(endBlock)
 			if err != nil {
-				l.log.Error("Failed to get events for block", "block", currentBlock, "err", err)
+				l.log.Error("Unable to get block hash", "block", endBlock, "err", err)
 				retry--
+				time.Sleep(BlockRetryInterval)
+				continue
+			} else if hash != endHash {
+				l.log.Warn("Block changed while fetching deposits, retrying", "block", endBlock, "hash", endHash, "new", hash)
+				retry--
+				time.Sleep(BlockRetryInterval)
 				continue
 			}
 
+			deposits, err := l.handleDepositLogs(logs)
+			if err != nil {
+				l.log.Error("Failed to handle deposits for block range", "from", currentBlock, "to", endBlock, "err", err)
+				retry--
+				continue
+			}
+
+			l.processed.push(processedRange{start: currentBlock, end: endBlock, hash: endHash, deposits: deposits})
+			if l.rescanEnd != nil && endBlock.Cmp(l.rescanEnd) >= 0 {
+				l.finishRescan()
+			}
+
 			// Write to block store. Not a critical operation, no need to retry
-			err = l.blockstore.StoreBlock(currentBlock)
+			err = l.blockstore.StoreBlock(endBlock)
 			if err != nil {
-				l.log.Error("Failed to write latest block to blockstore", "block", currentBlock, "err", err)
+				l.log.Error("Failed to write latest block to blockstore", "block", endBlock, "err", err)
 			}
 
 			if l.metrics != nil {
-				l.metrics.BlocksProcessed.Inc()
+				l.metrics.BlocksProcessed.Add(float64(new(big.Int).Sub(endBlock, currentBlock).Int64() + 1))
 				l.metrics.LatestProcessedBlock.Set(float64(latestBlock.Int64()))
 			}
 
@@ -171,13 +294,62 @@ func (l *listener) pollBlocks() error {
 			l.latestBlock.LastUpdated = time.Now()
 
 			// Goto next block and reset retry counter
-			currentBlock.Add(currentBlock, big.NewInt(1))
+			currentBlock = new(big.Int).Add(endBlock, big.NewInt(1))
 			retry = BlockRetryLimit
 			time.Sleep(blockSuccessRetryInterval)
 		}
 	}
 }
 
+// rangeEnd returns the last block of the range starting at current. The range never exceeds blockRange blocks
+// and never includes blocks with less than the required number of confirmations.
+func rangeEnd(current, latest, confirmations, blockRange *big.Int) *big.Int {
+	end := new(big.Int).Add(current, blockRange)
+	end.Sub(end, big.NewInt(1))
+
+	confirmed := new(big.Int).Sub(latest, confirmations)
+	if end.Cmp(confirmed) == 1 {
+		end.Set(confirmed)
+	}
+	if end.Cmp(current) == -1 {
+		end.Set(current)
+	}
+	return end
+}
+
+// isRangeTooLarge reports whether err is a node rejecting a log query for covering too many blocks or results.
+// Nodes do not agree on an error code for this, so the message is matched against known responses. Rate limit
+// errors are never treated as too large a range, as reducing the range only increases the number of requests.
+func isRangeTooLarge(err error) bool {
+	errMsg := strings.ToLower(err.Error())
+	for _, s := range rateLimitErrors {
+		if strings.Contains(errMsg, s) {
+			return false
+		}
+	}
+	for _, s := range rangeTooLargeErrors {
+		if strings.Contains(errMsg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var rangeTooLargeErrors = []string{
+	"query returned more than",
+	"maximum block range",
+	"range too large",
+	"range is too large",
+	"too many blocks",
+	"response size exceeded",
+	"query timeout exceeded",
+}
+
+var rateLimitErrors = []string{
+	"rate limit",
+	"too many requests",
+}
+
 func (l *listener) UnpackDepositEventLog(abi abi.ABI, data []byte) (*DepositLogs, error) {
 	var dl DepositLogs
 
@@ -191,39 +363,61 @@ func (l *listener) UnpackDepositEventLog(abi abi.ABI, data []byte) (*DepositLogs
 
 // getDepositEventsForBlock looks for the deposit event in the latest block
 func (l *listener) getDepositEventsForBlock(latestBlock *big.Int) error {
-	l.log.Debug("Querying block for deposit events", "block", latestBlock)
-
-	query := buildQuery(l.cfg.bridgeContract, utils.Deposit, latestBlock, latestBlock)
-	depositLogs := make([]*DepositLogs, 0)
+	_, err := l.getDepositEventsForRange(latestBlock, latestBlock)
+	return err
+}
 
-	// querying for logs
-	logs, err := l.conn.Client().FilterLogs(context.Background(), query)
+// getDepositEventsForRange looks for deposit events in the blocks from startBlock to endBlock inclusive and
+// returns the messages found. Deposits that were already routed before a reorganization are not routed again.
+func (l *listener) getDepositEventsForRange(startBlock *big.Int, endBlock *big.Int) ([]msg.Message, error) {
+	logs, err := l.fetchDepositLogs(startBlock, endBlock)
 	if err != nil {
-		return fmt.Errorf("unable to Filter Logs: %w", err)
+		return nil, err
 	}
+	return l.handleDepositLogs(logs)
+}
 
-	abiBridge, err := abi.JSON(strings.NewReader(Bridge.BridgeABI))
-	if err != nil {
-		return err
+// fetchDepositLogs queries the deposit event logs of the blocks from startBlock to endBlock inclusive
+func (l *listener) fetchDepositLogs(startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	l.log.Debug("Querying blocks for deposit events", "from", startBlock, "to", endBlock)
+
+	query := buildQuery(l.cfg.bridgeContract, utils.Deposit, startBlock, endBlock)
+
+	// querying for logs
+	logs, err := l.conn.Backend().FilterLogs(context.Background(), query)
+	if err != nil && isRangeTooLarge(err) {
+		return nil, fmt.Errorf("%w: %s", ErrBlockRangeTooLarge, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to Filter Logs: %w", err)
 	}
+	return logs, nil
+}
 
+// handleDepositLogs constructs and routes a message for each deposit event in logs, returning the messages
+func (l *listener) handleDepositLogs(logs []types.Log) ([]msg.Message, error) {
+	depositLogs := make([]*DepositLogs, 0)
+	depositBlocks := make([]uint64, 0)
 	for _, logData := range logs {
-		dl, err := l.UnpackDepositEventLog(abiBridge, logData.Data)
+		dl, err := l.UnpackDepositEventLog(bridgeABI, logData.Data)
 		if err != nil {
-			log.Error().Msgf("failed unpacking deposit event log: %v", err)
+			l.log.Error("Failed unpacking deposit event log", "tx", logData.TxHash, "err", err)
 			continue
 		}
 		depositLogs = append(depositLogs, dl)
+		depositBlocks = append(depositBlocks, logData.BlockNumber)
 	}
 
-	// read through the log events and handle their deposit event if handler is recognized
-	for _, depositLog := range depositLogs {
+	// read through the log events and handle their deposit event if handler is recognized. Nothing is routed until
+	// every log is handled, so a range that fails part way is not routed twice when it is retried.
+	deposits := make([]msg.Message, 0, len(depositLogs))
+	blocks := make([]uint64, 0, len(depositLogs))
+	for i, depositLog := range depositLogs {
 		var m msg.Message
 
-		addr, err := l.bridgeContract.ResourceIDToHandlerAddress(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, depositLog.ResourceID)
+		addr, err := l.bridgeContract.ResourceIDToHandlerAddress(l.conn.CallOpts(), depositLog.ResourceID)
 
 		if err != nil {
-			return fmt.Errorf("failed to get handler from resource ID %x", depositLog.ResourceID)
+			return nil, fmt.Errorf("failed to get handler from resource ID %x", depositLog.ResourceID)
 		}
 
 		if addr == l.cfg.erc20HandlerContract {
@@ -234,20 +428,137 @@ func (l *listener) getDepositEventsForBlock(latestBlock *big.Int) error {
 			m, err = l.handleGenericDepositedEvent(msg.ChainId(depositLog.DestinationDomainID), msg.Nonce(depositLog.DepositNonce))
 		} else {
 			l.log.Error("event has unrecognized handler", "handler", addr.Hex())
-			return nil
+			continue
 		}
 
-		if err != nil {
-			return err
+		if errors.Is(err, amount.ErrDust) {
+			l.depositLogger(msg.ChainId(depositLog.DestinationDomainID), msg.Nonce(depositLog.DepositNonce)).Error("Rejected deposit", "sender", depositLog.SenderAddress, "err", err)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, m)
+		blocks = append(blocks, depositBlocks[i])
+	}
+
+	for i, m := range deposits {
+		if routed, ok := l.reorged[depositKey(m)]; ok {
+			delete(l.reorged, depositKey(m))
+			if reflect.DeepEqual(routed, m) {
+				chains.MessageLogger(l.log, m).Debug("Deposit unchanged by reorganization, skipping", "dst", m.Destination, "nonce", m.DepositNonce)
+				continue
+			}
+			l.flagReorgedDeposit(routed)
 		}
 
-		err = l.router.Send(m)
+		if l.lifecycle != nil {
+			l.lifecycle.DepositDetected(m, l.blockTime(blocks[i]))
+		}
+		err := l.router.Send(m)
 		if err != nil {
-			l.log.Error("subscription error: failed to route message", "err", err)
+			chains.MessageLogger(l.log, m).Error("subscription error: failed to route message", "err", err)
 		}
 	}
 
-	return nil
+	return deposits, nil
+}
+
+// depositLogger returns the listener logger with the correlation ID of the deposit to destId with nonce
+func (l *listener) depositLogger(destId msg.ChainId, nonce msg.Nonce) log15.Logger {
+	return l.log.New(chains.CorrelationKey, chains.CorrelationID(l.cfg.id, destId, nonce))
+}
+
+// blockTime returns the time block n was produced, or the current time if it cannot be fetched
+func (l *listener) blockTime(n uint64) time.Time {
+	header, err := l.conn.Backend().HeaderByNumber(context.Background(), new(big.Int).SetUint64(n))
+	if err != nil {
+		l.log.Warn("Failed to fetch deposit block time", "block", n, "err", err)
+		return time.Now()
+	}
+	return time.Unix(int64(header.Time), 0)
+}
+
+// blockHash returns the hash of the canonical block at height n
+func (l *listener) blockHash(n *big.Int) (ethcommon.Hash, error) {
+	header, err := l.conn.Backend().HeaderByNumber(context.Background(), n)
+	if err != nil {
+		return ethcommon.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// checkReorg compares the parent hash of currentBlock with the last processed block. If they differ the chain has
+// been reorganized and the block to resume polling from is returned, otherwise nil.
+func (l *listener) checkReorg(currentBlock *big.Int) (*big.Int, error) {
+	last, ok := l.processed.last()
+	if !ok {
+		return nil, nil
+	}
+
+	header, err := l.conn.Backend().HeaderByNumber(context.Background(), currentBlock)
+	if err != nil {
+		return nil, err
+	}
+	if header.ParentHash == last.hash {
+		return nil, nil
+	}
+	return l.handleReorg(last)
+}
+
+// handleReorg rewinds the processed ranges to the last one still on the canonical chain and remembers the deposits
+// routed from the dropped ranges, so the re-scan can skip those that are unchanged and flag those that vanished.
+// The reported depth is an upper bound, as the listener only knows the hash of the last block of each range.
+func (l *listener) handleReorg(last processedRange) (*big.Int, error) {
+	dropped, found, err := l.processed.rewind(l.blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) == 0 {
+		return nil, fmt.Errorf("parent hash of block %s does not match, but processed blocks are canonical", new(big.Int).Add(last.end, big.NewInt(1)))
+	}
+
+	rescanFrom := dropped[len(dropped)-1].start
+	depth := new(big.Int).Sub(last.end, rescanFrom)
+	depth.Add(depth, big.NewInt(1))
+	if found {
+		l.log.Warn("Chain reorganization detected", "depth", depth, "from", rescanFrom, "to", last.end)
+	} else {
+		l.log.Error("Chain reorganization deeper than processed history", "depth", depth, "from", rescanFrom, "to", last.end)
+	}
+
+	for _, r := range dropped {
+		for _, m := range r.deposits {
+			l.reorged[depositKey(m)] = m
+		}
+	}
+	if l.rescanEnd == nil || last.end.Cmp(l.rescanEnd) > 0 {
+		l.rescanEnd = last.end
+	}
+
+	if l.relayerMetrics != nil {
+		l.relayerMetrics.ReorgsDetected.Inc()
+		l.relayerMetrics.LastReorgDepth.Set(float64(depth.Int64()))
+	}
+	return rescanFrom, nil
+}
+
+// finishRescan flags the deposits that were routed before a reorganization but not found again by the re-scan
+func (l *listener) finishRescan() {
+	for k, m := range l.reorged {
+		l.flagReorgedDeposit(m)
+		delete(l.reorged, k)
+	}
+	l.rescanEnd = nil
+}
+
+// flagReorgedDeposit reports that the routed deposit of m is no longer on the canonical chain, so writers that have
+// not voted on it yet no longer do
+func (l *listener) flagReorgedDeposit(m msg.Message) {
+	chains.MessageLogger(l.log, m).Error("Routed deposit is no longer on the canonical chain", "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+	l.flagged.Add(m)
+	if l.relayerMetrics != nil {
+		l.relayerMetrics.DepositsReorged.Inc()
+	}
 }
 
 // buildQuery constructs a query for the bridgeContract by hashing sig to get the event topic
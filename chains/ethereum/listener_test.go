@@ -4,6 +4,7 @@
 package ethereum
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -17,6 +18,7 @@ import (
 	"github.com/UltronFoundationDev/chainbridge/bindings/ERC20Handler"
 	"github.com/UltronFoundationDev/chainbridge/bindings/ERC721Handler"
 	"github.com/UltronFoundationDev/chainbridge/bindings/GenericHandler"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 	ethtest "github.com/UltronFoundationDev/chainbridge/shared/ethereum/testing"
 	"github.com/ethereum/go-ethereum/common"
@@ -272,3 +274,84 @@ func compareMessage(expected, actual msg.Message) error {
 	}
 	return nil
 }
+
+func TestRangeEnd(t *testing.T) {
+	confirmations := big.NewInt(10)
+	blockRange := big.NewInt(500)
+
+	// Far behind head, full range
+	end := rangeEnd(big.NewInt(100), big.NewInt(10000), confirmations, blockRange)
+	if end.Cmp(big.NewInt(599)) != 0 {
+		t.Fatalf("expected range to end at 599, got %s", end)
+	}
+
+	// Close to head, range is capped at the last confirmed block
+	end = rangeEnd(big.NewInt(100), big.NewInt(300), confirmations, blockRange)
+	if end.Cmp(big.NewInt(290)) != 0 {
+		t.Fatalf("expected range to end at 290, got %s", end)
+	}
+
+	// Single confirmed block
+	end = rangeEnd(big.NewInt(100), big.NewInt(110), confirmations, blockRange)
+	if end.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("expected range to end at 100, got %s", end)
+	}
+}
+
+func TestIsRangeTooLarge(t *testing.T) {
+	tooLarge := []string{
+		"query returned more than 10000 results",
+		"exceed maximum block range: 5000",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+	}
+	for _, e := range tooLarge {
+		if !isRangeTooLarge(errors.New(e)) {
+			t.Errorf("expected %q to be detected as range too large", e)
+		}
+	}
+
+	notTooLarge := []string{
+		"connection refused",
+		"limit exceeded",
+		"daily request count exceeded, request rate limited",
+		"429 Too Many Requests: block range 0x1-0x100",
+	}
+	for _, e := range notTooLarge {
+		if isRangeTooLarge(errors.New(e)) {
+			t.Errorf("unexpected range too large detection for %q", e)
+		}
+	}
+}
+
+func TestErc20DepositedEventDecimals(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	recipient := common.HexToAddress("0x0000000000000000000000000000000000000001").Bytes()
+	decimals := map[msg.ChainId]map[string][2]uint8{2: {rId.Hex(): {18, 6}}}
+	deposited, _ := new(big.Int).SetString("1000000000000000001", 10)
+	data := utils.ConstructErc20DepositData(recipient, deposited)
+
+	cfg := &Config{id: 1, decimals: decimals, dustPolicy: amount.DustTruncate}
+	l := NewListener(nil, cfg, TestLogger, nil, nil, nil, nil)
+	m, err := l.handleErc20DepositedEvent(2, 7, rId, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetBytes(m.Payload[0].([]byte)).Cmp(big.NewInt(1000000)) != 0 {
+		t.Fatalf("expected amount of 1000000, got %x", m.Payload[0])
+	}
+
+	// Other destinations are unchanged
+	m, err = l.handleErc20DepositedEvent(3, 8, rId, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetBytes(m.Payload[0].([]byte)).Cmp(deposited) != 0 {
+		t.Fatalf("expected amount of %s, got %x", deposited, m.Payload[0])
+	}
+
+	cfg.dustPolicy = amount.DustReject
+	l = NewListener(nil, cfg, TestLogger, nil, nil, nil, nil)
+	if _, err = l.handleErc20DepositedEvent(2, 7, rId, data); !errors.Is(err, amount.ErrDust) {
+		t.Fatalf("expected ErrDust, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+)
+
+// gasPriceSetter is implemented by connections whose gas price limits can be changed while running
+type gasPriceSetter interface {
+	SetGasPriceLimits(maxGasPrice *big.Int, gasMultiplier *big.Float)
+}
+
+// restartRequired returns the options that differ between old and new and cannot be applied to a running chain.
+// The start block is ignored, it only applies when the chain is initialized.
+func restartRequired(old, new *Config) []string {
+	fields := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"name", old.name, new.name},
+		{"endpoint", old.endpoint, new.endpoint},
+		{EndpointsOpt, old.endpoints, new.endpoints},
+		{"from", old.from, new.from},
+		{BridgeOpt, old.bridgeContract, new.bridgeContract},
+		{Erc20HandlerOpt, old.erc20HandlerContract, new.erc20HandlerContract},
+		{Erc721HandlerOpt, old.erc721HandlerContract, new.erc721HandlerContract},
+		{GenericHandlerOpt, old.genericHandlerContract, new.genericHandlerContract},
+		{GasLimitOpt, old.gasLimit.String(), new.gasLimit.String()},
+		{GasMarginOpt, old.gasMargin, new.gasMargin},
+		{MinGasPriceOpt, old.minGasPrice.String(), new.minGasPrice.String()},
+		{HttpOpt, old.http, new.http},
+		{BlockSuccessRetryIntervalOpt, old.blockSuccessRetryInterval.String(), new.blockSuccessRetryInterval.String()},
+		{BlockRangeOpt, old.blockRange.String(), new.blockRange.String()},
+		{TxTimeoutOpt, old.txTimeout, new.txTimeout},
+		{ReceiptTimeoutOpt, old.receiptTimeout, new.receiptTimeout},
+		{VoteOnlyOpt, old.voteOnly, new.voteOnly},
+		{SignerOpt, old.signer, new.signer},
+		{SignerUrlOpt, old.signerUrl, new.signerUrl},
+		{"decimals", old.decimals, new.decimals},
+		{DustPolicyOpt, old.dustPolicy, new.dustPolicy},
+		{EGSApiKey, old.egsApiKey, new.egsApiKey},
+		{EGSSpeed, old.egsSpeed, new.egsSpeed},
+		{GasPriceOraclesOpt, old.gasPriceOracles, new.gasPriceOracles},
+		{GasPricePercentileOpt, old.gasPricePercentile, new.gasPricePercentile},
+		{GasPriceOracleUrlOpt, old.gasPriceOracleUrl, new.gasPriceOracleUrl},
+		{GasPriceOracleJsonPathOpt, old.gasPriceOracleJsonPath, new.gasPriceOracleJsonPath},
+		{GasPriceOracleUnitOpt, old.gasPriceOracleUnit.String(), new.gasPriceOracleUnit.String()},
+		{FixedGasPriceOpt, old.fixedGasPrice.String(), new.fixedGasPrice.String()},
+		{FeeHistoryBlocksOpt, old.feeHistoryBlocks, new.feeHistoryBlocks},
+		{TipPercentileOpt, old.tipPercentile, new.tipPercentile},
+		{MinTipOpt, old.minTip.String(), new.minTip.String()},
+		{MaxTipOpt, old.maxTip.String(), new.maxTip.String()},
+		{BalanceThresholdOpt, old.balanceThreshold.String(), new.balanceThreshold.String()},
+		{VerifyDepositsOpt, old.verifyDeposits, new.verifyDeposits},
+		{VerifyConfirmationsOpt, old.verifyConfirmations.String(), new.verifyConfirmations.String()},
+	}
+
+	var changed []string
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.old, f.new) {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+// Reload applies maxGasPrice, gasMultiplier and blockConfirmations from chainCfg to the running chain. If any other
+// option changed, nothing is applied and an error listing the options is returned.
+func (c *Chain) Reload(chainCfg *core.ChainConfig) error {
+	cfg, err := parseChainConfig(chainCfg)
+	if err != nil {
+		return err
+	}
+
+	if changed := restartRequired(c.config, cfg); len(changed) != 0 {
+		return fmt.Errorf("changes to %s require a restart", strings.Join(changed, ", "))
+	}
+
+	if cfg.maxGasPrice.Cmp(c.config.maxGasPrice) != 0 || cfg.gasMultiplier.Cmp(c.config.gasMultiplier) != 0 {
+		if conn, ok := c.conn.(gasPriceSetter); ok {
+			conn.SetGasPriceLimits(cfg.maxGasPrice, cfg.gasMultiplier)
+		}
+		c.listener.log.Info("Updated gas price limits", "maxGasPrice", cfg.maxGasPrice, "gasMultiplier", cfg.gasMultiplier)
+		c.config.maxGasPrice = cfg.maxGasPrice
+		c.config.gasMultiplier = cfg.gasMultiplier
+	}
+
+	if cfg.blockConfirmations.Cmp(c.config.blockConfirmations) != 0 {
+		c.listener.setBlockConfirmations(cfg.blockConfirmations)
+		c.listener.log.Info("Updated block confirmations", "blockConfirmations", cfg.blockConfirmations)
+		c.config.blockConfirmations = cfg.blockConfirmations
+	}
+	return nil
+}
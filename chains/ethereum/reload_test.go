@@ -0,0 +1,90 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+)
+
+func newReloadTestConfig(opts map[string]string) *core.ChainConfig {
+	o := map[string]string{
+		"bridge":             "0x1234",
+		"maxGasPrice":        "20",
+		"gasMultiplier":      "1",
+		"blockConfirmations": "10",
+	}
+	for k, v := range opts {
+		o[k] = v
+	}
+	return &core.ChainConfig{
+		Name:     "chain",
+		Id:       1,
+		Endpoint: "endpoint",
+		From:     "0x0",
+		Opts:     o,
+	}
+}
+
+func TestReload(t *testing.T) {
+	cfg, err := parseChainConfig(newReloadTestConfig(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Chain{
+		config:   cfg,
+		listener: &listener{log: TestLogger, blockConfirmations: cfg.blockConfirmations},
+	}
+
+	err = c.Reload(newReloadTestConfig(map[string]string{
+		"maxGasPrice":        "30",
+		"gasMultiplier":      "1.5",
+		"blockConfirmations": "20",
+		"startBlock":         "100",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.listener.confirmations().Cmp(big.NewInt(20)) != 0 {
+		t.Fatalf("expected 20 block confirmations, got %s", c.listener.confirmations())
+	}
+	if c.config.maxGasPrice.Cmp(big.NewInt(30)) != 0 {
+		t.Fatalf("expected max gas price 30, got %s", c.config.maxGasPrice)
+	}
+
+	err = c.Reload(newReloadTestConfig(map[string]string{
+		"bridge":             "0x5678",
+		"blockConfirmations": "30",
+	}))
+	if err == nil {
+		t.Fatal("expected bridge change to be rejected")
+	}
+	if c.listener.confirmations().Cmp(big.NewInt(20)) != 0 {
+		t.Fatal("rejected reload should not apply block confirmations")
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	old, err := parseChainConfig(newReloadTestConfig(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := parseChainConfig(newReloadTestConfig(map[string]string{
+		"bridge":      "0x5678",
+		"gasLimit":    "100",
+		"maxGasPrice": "30",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := restartRequired(old, new)
+	expected := []string{BridgeOpt, GasLimitOpt}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected %v, got %v", expected, changed)
+	}
+}
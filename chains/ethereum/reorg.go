@@ -0,0 +1,71 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// ReorgHistory is the number of processed block ranges the listener remembers to detect reorganizations
+var ReorgHistory = 128
+
+// processedRange is a range of blocks the listener has finished processing
+type processedRange struct {
+	start    *big.Int
+	end      *big.Int
+	hash     ethcommon.Hash // Hash of the end block when it was processed
+	deposits []msg.Message  // Messages routed from the range
+}
+
+// blockRing holds the most recently processed ranges, oldest first
+type blockRing struct {
+	ranges []processedRange
+	size   int
+}
+
+func newBlockRing(size int) *blockRing {
+	return &blockRing{size: size}
+}
+
+func (r *blockRing) push(p processedRange) {
+	if len(r.ranges) == r.size {
+		r.ranges = r.ranges[1:]
+	}
+	r.ranges = append(r.ranges, p)
+}
+
+func (r *blockRing) last() (processedRange, bool) {
+	if len(r.ranges) == 0 {
+		return processedRange{}, false
+	}
+	return r.ranges[len(r.ranges)-1], true
+}
+
+// rewind drops every range that is no longer on the canonical chain, as reported by hashAt, and returns them
+// newest first. Returns false if no remaining range could be confirmed canonical, ie. the reorg is deeper than the
+// ring's history.
+func (r *blockRing) rewind(hashAt func(*big.Int) (ethcommon.Hash, error)) ([]processedRange, bool, error) {
+	var dropped []processedRange
+	for i := len(r.ranges) - 1; i >= 0; i-- {
+		hash, err := hashAt(r.ranges[i].end)
+		if err != nil {
+			return nil, false, err
+		}
+		if hash == r.ranges[i].hash {
+			r.ranges = r.ranges[:i+1]
+			return dropped, true, nil
+		}
+		dropped = append(dropped, r.ranges[i])
+	}
+	r.ranges = r.ranges[:0]
+	return dropped, false, nil
+}
+
+func depositKey(m msg.Message) string {
+	return fmt.Sprintf("%d-%d", m.Destination, m.DepositNonce)
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestBlockRingRewind(t *testing.T) {
+	ring := newBlockRing(3)
+	for i := int64(1); i <= 4; i++ {
+		ring.push(processedRange{start: big.NewInt(i * 10), end: big.NewInt(i*10 + 9), hash: ethcommon.Hash{byte(i)}})
+	}
+	if len(ring.ranges) != 3 || ring.ranges[0].start.Int64() != 20 {
+		t.Fatalf("expected oldest range to be dropped, got %d ranges", len(ring.ranges))
+	}
+
+	// Blocks after 29 were replaced
+	canonical := map[int64]ethcommon.Hash{29: {2}, 39: {0xff}, 49: {0xff}}
+	hashAt := func(n *big.Int) (ethcommon.Hash, error) {
+		return canonical[n.Int64()], nil
+	}
+
+	dropped, found, err := ring.rewind(hashAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("expected common ancestor to be found")
+	}
+	if len(dropped) != 2 || dropped[0].start.Int64() != 40 || dropped[1].start.Int64() != 30 {
+		t.Fatalf("unexpected dropped ranges: %v", dropped)
+	}
+	if last, _ := ring.last(); last.end.Int64() != 29 {
+		t.Fatalf("expected ring to end at block 29, got %s", last.end)
+	}
+
+	// Nothing left is canonical
+	canonical[29] = ethcommon.Hash{0xff}
+	dropped, found, err = ring.rewind(hashAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found || len(dropped) != 1 {
+		t.Fatalf("expected reorg deeper than history, found: %t, dropped: %d", found, len(dropped))
+	}
+	if _, ok := ring.last(); ok {
+		t.Fatal("expected ring to be empty")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/blockstore"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Replay parses the deposits in block, or only those of the transaction txHash if it is set, and routes the
+// resulting messages with r. The relayer's key is not loaded and nothing is written to the blockstore.
+func Replay(chainCfg *core.ChainConfig, logger log15.Logger, r chains.Router, block *big.Int, txHash *common.Hash) error {
+	cfg, err := parseChainConfig(chainCfg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := connectReadOnly(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	contracts, err := bindContracts(conn, cfg)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan int)
+	defer close(stop)
+	l := NewListener(conn, cfg, logger, &blockstore.EmptyStore{}, stop, nil, nil)
+	l.setContracts(contracts.bridge, contracts.erc20Handler, contracts.erc721Handler, contracts.genericHandler)
+	l.setRouter(r)
+
+	if txHash == nil {
+		_, err = l.getDepositEventsForRange(block, block)
+		return err
+	}
+
+	receipt, err := conn.TransactionReceipt(context.Background(), *txHash)
+	if err != nil {
+		return fmt.Errorf("unable to fetch receipt of %s: %w", txHash.Hex(), err)
+	}
+
+	logs := depositLogs(receipt, cfg.bridgeContract)
+	if len(logs) == 0 {
+		return fmt.Errorf("transaction %s has no deposit events", txHash.Hex())
+	}
+	_, err = l.handleDepositLogs(logs)
+	return err
+}
+
+// depositLogs returns the Deposit events emitted by the bridge in receipt
+func depositLogs(receipt *types.Receipt, bridge common.Address) []types.Log {
+	logs := make([]types.Log, 0)
+	for _, lg := range receipt.Logs {
+		if lg.Address == bridge && len(lg.Topics) != 0 && lg.Topics[0] == utils.Deposit.GetTopic() {
+			logs = append(logs, *lg)
+		}
+	}
+	return logs
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"testing"
+
+	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDepositLogs(t *testing.T) {
+	bridge := common.HexToAddress("0x62877dDCd49aD22f5eDfc6ac108e9a4b5D2bD88B")
+	other := common.HexToAddress("0x3167776db165D8eA0f51790CA2bbf44Db5105ADF")
+	deposit := utils.Deposit.GetTopic()
+	receipt := &types.Receipt{Logs: []*types.Log{
+		{Address: bridge, Topics: []common.Hash{deposit}, Index: 0},
+		{Address: other, Topics: []common.Hash{deposit}, Index: 1},
+		{Address: bridge, Topics: []common.Hash{common.HexToHash("0x01")}, Index: 2},
+		{Address: bridge, Index: 3},
+		{Address: bridge, Topics: []common.Hash{deposit}, Index: 4},
+	}}
+
+	logs := depositLogs(receipt, bridge)
+	if len(logs) != 2 || logs[0].Index != 0 || logs[1].Index != 4 {
+		t.Fatalf("expected the bridge deposit logs 0 and 4, got %v", logs)
+	}
+}
@@ -45,11 +45,15 @@ func createConfig(name string, startBlock *big.Int, contracts *utils.DeployedCon
 		erc721HandlerContract:  common.Address{},
 		genericHandlerContract: common.Address{},
 		gasLimit:               big.NewInt(DefaultGasLimit),
+		gasMargin:              DefaultGasMargin,
 		maxGasPrice:            big.NewInt(DefaultGasPrice),
 		gasMultiplier:          big.NewFloat(DefaultGasMultiplier),
 		http:                   false,
 		startBlock:             startBlock,
 		blockConfirmations:     big.NewInt(3),
+		blockRange:             big.NewInt(DefaultBlockRange),
+		txTimeout:              time.Second * DefaultTxTimeout,
+		receiptTimeout:         time.Second * DefaultReceiptTimeout,
 	}
 
 	if contracts != nil {
@@ -70,7 +74,7 @@ func newTestLogger(name string) log15.Logger {
 
 func newLocalConnection(t *testing.T, cfg *Config) *connection.Connection {
 	kp := keystore.TestKeyRing.EthereumKeys[cfg.from]
-	conn := connection.NewConnection(TestEndpoint, false, kp, TestLogger, big.NewInt(DefaultGasLimit), big.NewInt(DefaultGasPrice), big.NewInt(DefaultMinGasPrice), big.NewFloat(DefaultGasMultiplier), "", "")
+	conn := connection.NewConnection(TestEndpoint, false, kp, TestLogger, big.NewInt(DefaultGasLimit), big.NewInt(DefaultGasPrice), big.NewInt(DefaultMinGasPrice), big.NewFloat(DefaultGasMultiplier))
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
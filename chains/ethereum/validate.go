@@ -0,0 +1,72 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	connection "github.com/UltronFoundationDev/chainbridge/connections/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// addressSigner identifies the relayer account without being able to sign, so a config can be checked or the chain
+// read without unlocking the keystore
+type addressSigner common.Address
+
+func (s addressSigner) Address() common.Address {
+	return common.Address(s)
+}
+
+func (s addressSigner) SignTx(_ *types.Transaction, _ *big.Int) (*types.Transaction, error) {
+	return nil, errors.New("signing is not available without loading the keystore")
+}
+
+// ValidateConfig runs the same checks as InitializeChain without loading keys or starting the chain. The network
+// checks (connecting to the endpoints, contract bytecode and chain ID) are skipped if offline is set.
+func ValidateConfig(chainCfg *core.ChainConfig, logger log15.Logger, offline bool) error {
+	// parseChainConfig consumes the opts, leave the caller's intact
+	opts := make(map[string]string, len(chainCfg.Opts))
+	for k, v := range chainCfg.Opts {
+		opts[k] = v
+	}
+	copyCfg := *chainCfg
+	copyCfg.Opts = opts
+
+	cfg, err := parseChainConfig(&copyCfg)
+	if err != nil {
+		return err
+	}
+	if !common.IsHexAddress(cfg.from) {
+		return fmt.Errorf("from must be an address, got %q", cfg.from)
+	}
+
+	if offline {
+		return nil
+	}
+
+	conn, err := connectReadOnly(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return checkContracts(conn, cfg)
+}
+
+// connectReadOnly connects to the chain's endpoints without loading the relayer's key
+func connectReadOnly(cfg *Config, logger log15.Logger) (*connection.Connection, error) {
+	conn := connection.NewConnection(cfg.endpoint, cfg.http, nil, logger, cfg.gasLimit, cfg.maxGasPrice, cfg.minGasPrice, cfg.gasMultiplier)
+	conn.AddEndpoints(cfg.endpoints...)
+	conn.SetSigner(addressSigner(common.HexToAddress(cfg.from)))
+	err := conn.Connect()
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", cfg.endpoint, err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+var _ chains.DepositVerifier = &listener{}
+
+// verifyConfirmations returns the number of confirmations a deposit needs to be verified
+func (l *listener) verifyConfirmations() *big.Int {
+	if l.cfg.verifyConfirmations != nil {
+		return l.cfg.verifyConfirmations
+	}
+	return l.confirmations()
+}
+
+// VerifyDeposit reads the deposit of m from the state of the bridge at the latest block minus the required
+// confirmations. A deposit recorded in that state is in a canonical block with at least as many confirmations.
+func (l *listener) VerifyDeposit(m msg.Message) error {
+	latest, err := l.conn.LatestBlock()
+	if err != nil {
+		return err
+	}
+	opts := *l.conn.CallOpts()
+	opts.BlockNumber = new(big.Int).Sub(latest, l.verifyConfirmations())
+	if opts.BlockNumber.Sign() < 0 {
+		return chains.ErrUnconfirmed
+	}
+
+	data, err := l.bridgeContract.DepositRecords(&opts, uint64(m.DepositNonce), uint8(m.Destination))
+	if err != nil {
+		return fmt.Errorf("unable to read deposit record: %w", err)
+	}
+	if len(data) == 0 {
+		// The deposit may be too recent, check if it is known at all
+		data, err = l.bridgeContract.DepositRecords(l.conn.CallOpts(), uint64(m.DepositNonce), uint8(m.Destination))
+		if err != nil {
+			return fmt.Errorf("unable to read deposit record: %w", err)
+		}
+		if len(data) != 0 {
+			return chains.ErrUnconfirmed
+		}
+		return fmt.Errorf("deposit %d to chain %d not found", m.DepositNonce, m.Destination)
+	}
+
+	deposit, err := l.depositAt(&opts, m)
+	if err != nil {
+		return err
+	}
+	return chains.MatchDeposit(m, deposit)
+}
+
+// depositAt constructs the message of the deposit of m from the handler records at the block of opts
+func (l *listener) depositAt(opts *bind.CallOpts, m msg.Message) (msg.Message, error) {
+	handler, err := l.bridgeContract.ResourceIDToHandlerAddress(opts, m.ResourceId)
+	if err != nil {
+		return msg.Message{}, fmt.Errorf("failed to get handler from resource ID %x: %w", m.ResourceId, err)
+	}
+
+	nonce, destId := uint64(m.DepositNonce), uint8(m.Destination)
+	switch handler {
+	case l.cfg.erc20HandlerContract:
+		record, err := l.erc20HandlerContract.GetDepositRecord(opts, nonce, destId)
+		if err != nil {
+			return msg.Message{}, fmt.Errorf("unable to read ERC20 deposit record: %w", err)
+		}
+		conv, err := l.amounts.Convert(m.Destination, record.ResourceID, record.Amount)
+		if err != nil {
+			return msg.Message{}, err
+		}
+		return msg.NewFungibleTransfer(l.cfg.id, m.Destination, m.DepositNonce, conv.Amount, record.ResourceID, record.DestinationRecipientAddress), nil
+	case l.cfg.erc721HandlerContract:
+		record, err := l.erc721HandlerContract.GetDepositRecord(opts, nonce, destId)
+		if err != nil {
+			return msg.Message{}, fmt.Errorf("unable to read ERC721 deposit record: %w", err)
+		}
+		return msg.NewNonFungibleTransfer(l.cfg.id, m.Destination, m.DepositNonce, record.ResourceID, record.TokenID, record.DestinationRecipientAddress, record.MetaData), nil
+	case l.cfg.genericHandlerContract:
+		record, err := l.genericHandlerContract.GetDepositRecord(opts, nonce, destId)
+		if err != nil {
+			return msg.Message{}, fmt.Errorf("unable to read generic deposit record: %w", err)
+		}
+		return msg.NewGenericTransfer(l.cfg.id, m.Destination, m.DepositNonce, record.ResourceID, record.MetaData[:]), nil
+	default:
+		return msg.Message{}, fmt.Errorf("resource ID %x has unrecognized handler %s", m.ResourceId, handler.Hex())
+	}
+}
@@ -9,6 +9,9 @@ import (
 	metrics "github.com/UltronFoundationDev/chainbridge-utils/metrics/types"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
 	"github.com/UltronFoundationDev/chainbridge/bindings/Bridge"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
 )
 
 var _ core.Writer = &writer{}
@@ -26,6 +29,13 @@ type writer struct {
 	stop           <-chan int
 	sysErr         chan<- error // Reports fatal error to core
 	metrics        *metrics.ChainMetrics
+	relayerMetrics *chains.Metrics
+	lifecycle      *chains.LifecycleMetrics
+	policy         *policy.Policy
+	verifier       *chains.Verifier        // Re-checks deposits on the source chain before voting, may be nil
+	reorged        *chains.ReorgedDeposits // Deposits flagged as no longer canonical by their listener, may be nil
+	queue          *msgqueue.Queue         // Durable record of received messages, may be nil
+	drainer        *chains.Drainer         // Tracks pending votes and executions so they can complete on shutdown
 }
 
 // NewWriter creates and returns writer
@@ -37,11 +47,15 @@ func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int,
 		stop:    stop,
 		sysErr:  sysErr,
 		metrics: m,
+		drainer: chains.NewDrainer(log, "transactions"),
 	}
 }
 
 func (w *writer) start() error {
 	w.log.Debug("Starting ethereum writer...")
+	if w.queue != nil {
+		go w.replayMessages()
+	}
 	return nil
 }
 
@@ -50,10 +64,109 @@ func (w *writer) setContract(bridge *Bridge.Bridge) {
 	w.bridgeContract = bridge
 }
 
+// setMetrics sets the relayer metrics, which are optional
+func (w *writer) setMetrics(m *chains.Metrics) {
+	w.relayerMetrics = m
+}
+
+// setReorgedDeposits sets the deposits the writer refuses to vote on as they are no longer on the canonical chain
+func (w *writer) setReorgedDeposits(r *chains.ReorgedDeposits) {
+	w.reorged = r
+}
+
+// setLifecycleMetrics sets the message lifecycle metrics, which are optional
+func (w *writer) setLifecycleMetrics(m *chains.LifecycleMetrics) {
+	w.lifecycle = m
+}
+
+// setPolicy sets the policy messages are checked against before voting, which is optional
+func (w *writer) setPolicy(p *policy.Policy) {
+	w.policy = p
+}
+
+// setVerifier sets the verifier deposits are re-checked with before voting, which is optional
+func (w *writer) setVerifier(v *chains.Verifier) {
+	w.verifier = v
+}
+
+// setQueue adds the message queue used to persist in-flight messages
+func (w *writer) setQueue(q *msgqueue.Queue) {
+	w.queue = q
+}
+
+// replayMessages resolves every message that was received or voted on but not yet executed, eg. before a restart.
+// Proposals this relayer has already voted on are watched for finalization again.
+func (w *writer) replayMessages() {
+	states := []msgqueue.State{msgqueue.Received, msgqueue.Voted}
+	if w.cfg.voteOnly {
+		states = states[:1]
+	}
+
+	for _, e := range w.queue.Entries(states...) {
+		m := e.Message
+		log := w.messageLog(m)
+		log.Info("Replaying unfinished message", "src", m.Source, "nonce", m.DepositNonce, "state", e.State)
+
+		// A message that was voted on but has not passed yet will not be resolved again, so resume watching it
+		if !w.ResolveMessage(m) {
+			if state, _ := w.queue.State(m); state != msgqueue.Voted {
+				continue
+			}
+
+			data, dataHash, err := w.proposalData(m)
+			if err != nil {
+				log.Error("Unable to construct proposal data", "err", err)
+				continue
+			}
+			latestBlock, err := w.conn.LatestBlock()
+			if err != nil {
+				log.Error("Unable to fetch latest block", "err", err)
+				continue
+			}
+			go w.watchThenExecute(m, data, dataHash, latestBlock)
+		}
+	}
+}
+
+// resubmit resolves a message from the queue again, eg. one that failed. The message is resolved in the background.
+func (w *writer) resubmit(m msg.Message) {
+	w.messageLog(m).Info("Re-submitting message", "src", m.Source, "nonce", m.DepositNonce)
+	w.setMessageState(m, msgqueue.Received)
+	go w.ResolveMessage(m)
+}
+
+// messageLog returns the writer logger with the correlation ID of m
+func (w *writer) messageLog(m msg.Message) log15.Logger {
+	return chains.MessageLogger(w.log, m)
+}
+
+// setMessageState records the state of a message in the queue, if one is configured
+func (w *writer) setMessageState(m msg.Message, s msgqueue.State) {
+	if w.queue == nil {
+		return
+	}
+	err := w.queue.SetState(m, s)
+	if err != nil {
+		w.messageLog(m).Error("Failed to update message queue", "src", m.Source, "nonce", m.DepositNonce, "state", s, "err", err)
+	}
+}
+
+// checks returns the checks messages must pass before the writer votes on them
+func (w *writer) checks() chains.Checks {
+	return chains.Checks{Queue: w.queue, Policy: w.policy, Verifier: w.verifier, Reorged: w.reorged}
+}
+
 // ResolveMessage handles any given message based on type
 // A bool is returned to indicate failure/success, this should be ignored except for within tests.
 func (w *writer) ResolveMessage(m msg.Message) bool {
-	w.log.Info("Attempting to resolve message", "type", m.Type, "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+	log := w.messageLog(m)
+	log.Info("Attempting to resolve message", "type", m.Type, "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+
+	done := w.drainer.Admit(m, "resolve", w.checks(), log)
+	if done == nil {
+		return false
+	}
+	defer done()
 
 	switch m.Type {
 	case msg.FungibleTransfer:
@@ -63,7 +176,8 @@ func (w *writer) ResolveMessage(m msg.Message) bool {
 	case msg.GenericTransfer:
 		return w.createGenericDepositProposal(m)
 	default:
-		w.log.Error("Unknown message type received", "type", m.Type)
+		log.Error("Unknown message type received", "type", m.Type)
+		w.setMessageState(m, msgqueue.Failed)
 		return false
 	}
 }
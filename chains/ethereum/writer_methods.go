@@ -6,14 +6,20 @@ package ethereum
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/UltronFoundationDev/chainbridge/bindings/Bridge"
+	connection "github.com/UltronFoundationDev/chainbridge/connections/ethereum"
+	eth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
 	"strings"
 	"time"
 
-	log "github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/ethereum"
 )
 
@@ -26,46 +32,80 @@ const TxRetryInterval = time.Second * 2
 // Maximum number of tx retries before exiting
 const TxRetryLimit = 10
 
+// Time between checking whether a submitted tx has been mined
+var TxReceiptPollInterval = time.Second * 5
+
 var ErrNonceTooLow = errors.New("nonce too low")
 var ErrTxUnderpriced = errors.New("replacement transaction underpriced")
 var ErrFatalTx = errors.New("submission of transaction failed")
 var ErrFatalQuery = errors.New("query of chain state failed")
+var ErrTxReverted = errors.New("transaction reverted")
+var ErrReceiptTimeout = errors.New("timed out waiting for transaction to be mined")
+var errWriterStopped = errors.New("writer stopped")
+
+// bridgeABI is the parsed ABI of the bridge contract, used to pack calls and unpack events
+var bridgeABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(Bridge.BridgeABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid bridge ABI: %s", err))
+	}
+	return parsed
+}()
 
 // proposalIsComplete returns true if the proposal state is either Passed, Transferred or Cancelled
-func (w *writer) proposalIsComplete(srcId msg.ChainId, nonce msg.Nonce, dataHash [32]byte) bool {
-	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(srcId), uint64(nonce), dataHash)
+func (w *writer) proposalIsComplete(m msg.Message, dataHash [32]byte) bool {
+	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
 	if err != nil {
-		w.log.Error("Failed to check proposal existence", "err", err)
+		w.messageLog(m).Error("Failed to check proposal existence", "err", err)
 		return false
 	}
 
 	return prop.Status == PassedStatus || prop.Status == TransferredStatus || prop.Status == CancelledStatus
 }
 
-// proposalIsComplete returns true if the proposal state is Transferred or Cancelled
-func (w *writer) proposalIsFinalized(srcId msg.ChainId, nonce msg.Nonce, dataHash [32]byte) bool {
-	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(srcId), uint64(nonce), dataHash)
+// proposalIsFinalized returns true if the proposal state is Transferred or Cancelled
+func (w *writer) proposalIsFinalized(m msg.Message, dataHash [32]byte) bool {
+	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
 	if err != nil {
-		w.log.Error("Failed to check proposal existence", "err", err)
+		w.messageLog(m).Error("Failed to check proposal existence", "err", err)
 		return false
 	}
 	return prop.Status == TransferredStatus || prop.Status == CancelledStatus // Transferred (3)
 }
 
-func (w *writer) proposalIsPassed(srcId msg.ChainId, nonce msg.Nonce, dataHash [32]byte) bool {
-	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(srcId), uint64(nonce), dataHash)
+// proposalIsCancelled returns true if the proposal state is Cancelled
+func (w *writer) proposalIsCancelled(m msg.Message, dataHash [32]byte) bool {
+	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
+	if err != nil {
+		w.messageLog(m).Error("Failed to check proposal existence", "err", err)
+		return false
+	}
+	return prop.Status == CancelledStatus
+}
+
+// finalizedState returns the queue state of a message whose proposal is finalized, which is failed if the proposal
+// was cancelled and executed otherwise
+func (w *writer) finalizedState(m msg.Message, dataHash [32]byte) msgqueue.State {
+	if w.proposalIsCancelled(m, dataHash) {
+		return msgqueue.Failed
+	}
+	return msgqueue.Executed
+}
+
+func (w *writer) proposalIsPassed(m msg.Message, dataHash [32]byte) bool {
+	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
 	if err != nil {
-		w.log.Error("Failed to check proposal existence", "err", err)
+		w.messageLog(m).Error("Failed to check proposal existence", "err", err)
 		return false
 	}
 	return prop.Status == PassedStatus
 }
 
 // hasVoted checks if this relayer has already voted
-func (w *writer) hasVoted(srcId msg.ChainId, nonce msg.Nonce, dataHash [32]byte) bool {
-	hasVoted, err := w.bridgeContract.HasVotedOnProposal(w.conn.CallOpts(), utils.IDAndNonce(srcId, nonce), dataHash, w.conn.Opts().From)
+func (w *writer) hasVoted(m msg.Message, dataHash [32]byte) bool {
+	hasVoted, err := w.bridgeContract.HasVotedOnProposal(w.conn.CallOpts(), utils.IDAndNonce(m.Source, m.DepositNonce), dataHash, w.conn.Opts().From)
 	if err != nil {
-		w.log.Error("Failed to check proposal existence", "err", err)
+		w.messageLog(m).Error("Failed to check proposal existence", "err", err)
 		return false
 	}
 
@@ -73,18 +113,24 @@ func (w *writer) hasVoted(srcId msg.ChainId, nonce msg.Nonce, dataHash [32]byte)
 }
 
 func (w *writer) shouldVote(m msg.Message, dataHash [32]byte) bool {
+	log := w.messageLog(m)
+
 	// Check if proposal has passed and skip if Passed or Transferred
 
-	if w.proposalIsComplete(m.Source, m.DepositNonce, dataHash) {
-		w.log.Info("Proposal complete, not voting", "src", m.Source, "nonce", m.DepositNonce)
+	if w.proposalIsComplete(m, dataHash) {
+		log.Info("Proposal complete, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		if w.proposalIsFinalized(m, dataHash) {
+			w.setMessageState(m, w.finalizedState(m, dataHash))
+		}
 		return false
 	}
 
 	// Check if relayer has previously voted
 
-	if w.hasVoted(m.Source, m.DepositNonce, dataHash) {
+	if w.hasVoted(m, dataHash) {
 
-		w.log.Info("Relayer has already voted, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		log.Info("Relayer has already voted, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		w.setMessageState(m, msgqueue.Voted)
 		return false
 	}
 
@@ -94,16 +140,16 @@ func (w *writer) shouldVote(m msg.Message, dataHash [32]byte) bool {
 // creatceErc20Proposal creates an Erc20 proposal.
 // Returns true if the proposal is successfully created or is complete
 func (w *writer) createErc20Proposal(m msg.Message) bool {
-
-	w.log.Info("Creating erc20 proposal", "src", m.Source, "nonce", m.DepositNonce)
+	log := w.messageLog(m)
+	log.Info("Creating erc20 proposal", "src", m.Source, "nonce", m.DepositNonce)
 
 	data := ConstructErc20ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte))
 	dataHash := utils.Hash(append(w.cfg.erc20HandlerContract.Bytes(), data...))
 
 	if !w.shouldVote(m, dataHash) {
-		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
+		if w.proposalIsPassed(m, dataHash) {
 			// We should not vote for this proposal but it is ready to be executed
-			w.log.Info("Proposal event with status passed", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+			w.executeProposal(m, data, dataHash)
 			return true
 		} else {
 			return false
@@ -113,12 +159,12 @@ func (w *writer) createErc20Proposal(m msg.Message) bool {
 	// Capture latest block so when know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
-		w.log.Error("Unable to fetch latest block", "err", err)
+		log.Error("Unable to fetch latest block", "err", err)
 		return false
 	}
 
 	// watch for execution event
-	go w.watchThenExecute(m, latestBlock)
+	go w.watchThenExecute(m, data, dataHash, latestBlock)
 
 	w.voteProposal(m, data, dataHash)
 
@@ -128,13 +174,14 @@ func (w *writer) createErc20Proposal(m msg.Message) bool {
 // createErc721Proposal creates an Erc721 proposal.
 // Returns true if the proposal is succesfully created or is complete
 func (w *writer) createErc721Proposal(m msg.Message) bool {
-	w.log.Info("Creating erc721 proposal", "src", m.Source, "nonce", m.DepositNonce)
+	log := w.messageLog(m)
+	log.Info("Creating erc721 proposal", "src", m.Source, "nonce", m.DepositNonce)
 
 	data := ConstructErc721ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte), m.Payload[2].([]byte))
 	dataHash := utils.Hash(append(w.cfg.erc721HandlerContract.Bytes(), data...))
 
 	if !w.shouldVote(m, dataHash) {
-		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
+		if w.proposalIsPassed(m, dataHash) {
 			// We should not vote for this proposal but it is ready to be executed
 			w.executeProposal(m, data, dataHash)
 			return true
@@ -146,12 +193,12 @@ func (w *writer) createErc721Proposal(m msg.Message) bool {
 	// Capture latest block so we know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
-		w.log.Error("Unable to fetch latest block", "err", err)
+		log.Error("Unable to fetch latest block", "err", err)
 		return false
 	}
 
 	// watch for execution event
-	go w.watchThenExecute(m, latestBlock)
+	go w.watchThenExecute(m, data, dataHash, latestBlock)
 
 	w.voteProposal(m, data, dataHash)
 
@@ -161,7 +208,8 @@ func (w *writer) createErc721Proposal(m msg.Message) bool {
 // createGenericDepositProposal creates a generic proposal
 // returns true if the proposal is complete or is succesfully created
 func (w *writer) createGenericDepositProposal(m msg.Message) bool {
-	w.log.Info("Creating generic proposal", "src", m.Source, "nonce", m.DepositNonce)
+	log := w.messageLog(m)
+	log.Info("Creating generic proposal", "src", m.Source, "nonce", m.DepositNonce)
 
 	metadata := m.Payload[0].([]byte)
 	data := ConstructGenericProposalData(metadata)
@@ -169,7 +217,7 @@ func (w *writer) createGenericDepositProposal(m msg.Message) bool {
 	dataHash := utils.Hash(toHash)
 
 	if !w.shouldVote(m, dataHash) {
-		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
+		if w.proposalIsPassed(m, dataHash) {
 			// We should not vote for this proposal but it is ready to be executed
 			w.executeProposal(m, data, dataHash)
 			return true
@@ -181,36 +229,64 @@ func (w *writer) createGenericDepositProposal(m msg.Message) bool {
 	// Capture latest block so when know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
-		w.log.Error("Unable to fetch latest block", "err", err)
+		log.Error("Unable to fetch latest block", "err", err)
 		return false
 	}
 
 	// watch for execution event
-	go w.watchThenExecute(m, latestBlock)
+	go w.watchThenExecute(m, data, dataHash, latestBlock)
 
 	w.voteProposal(m, data, dataHash)
 
 	return true
 }
 
+// proposalData returns the proposal data for a message along with its hash
+func (w *writer) proposalData(m msg.Message) ([]byte, [32]byte, error) {
+	var data []byte
+	var handler common.Address
+	switch m.Type {
+	case msg.FungibleTransfer:
+		data = ConstructErc20ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte))
+		handler = w.cfg.erc20HandlerContract
+	case msg.NonFungibleTransfer:
+		data = ConstructErc721ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte), m.Payload[2].([]byte))
+		handler = w.cfg.erc721HandlerContract
+	case msg.GenericTransfer:
+		data = ConstructGenericProposalData(m.Payload[0].([]byte))
+		handler = w.cfg.genericHandlerContract
+	default:
+		return nil, [32]byte{}, fmt.Errorf("unknown message type %s", m.Type)
+	}
+	return data, utils.Hash(append(handler.Bytes(), data...)), nil
+}
+
 // watchThenExecute watches for the latest block and executes once the matching finalized event is found
-func (w *writer) watchThenExecute(m msg.Message, latestBlock *big.Int) {
-	w.log.Info("Watching for finalization event", "src", m.Source, "nonce", m.DepositNonce)
+func (w *writer) watchThenExecute(m msg.Message, data []byte, dataHash [32]byte, latestBlock *big.Int) {
+	if w.cfg.voteOnly {
+		return
+	}
+	log := w.messageLog(m)
+	log.Info("Watching for finalization event", "src", m.Source, "nonce", m.DepositNonce)
 
 	// watching for the latest block, querying and matching the finalized event will be retried up to ExecuteBlockWatchLimit times
 	for i := 0; i < ExecuteBlockWatchLimit; i++ {
 		select {
 		case <-w.stop:
 			return
+		case <-w.drainer.Draining():
+			// Voted messages are replayed on startup, which resumes the watch
+			log.Debug("Writer is shutting down, no longer watching for finalization", "src", m.Source, "nonce", m.DepositNonce)
+			return
 		default:
 			// watch for the lastest block, retry up to BlockRetryLimit times
 			for waitRetrys := 0; waitRetrys < BlockRetryLimit; waitRetrys++ {
 				err := w.conn.WaitForBlock(latestBlock, w.cfg.blockConfirmations)
 				if err != nil {
-					w.log.Error("Waiting for block failed", "err", err)
+					log.Error("Waiting for block failed", "err", err)
 					// Exit if retries exceeded
 					if waitRetrys+1 == BlockRetryLimit {
-						w.log.Error("Waiting for block retries exceeded, shutting down")
+						log.Error("Waiting for block retries exceeded, shutting down")
 						w.sysErr <- ErrFatalQuery
 						return
 					}
@@ -221,16 +297,15 @@ func (w *writer) watchThenExecute(m msg.Message, latestBlock *big.Int) {
 
 			// query for logs
 			query := buildQuery(w.cfg.bridgeContract, utils.ProposalEvent, latestBlock, latestBlock)
-			evts, err := w.conn.Client().FilterLogs(context.Background(), query)
-			parsed, err := abi.JSON(strings.NewReader(Bridge.BridgeABI))
+			evts, err := w.conn.Backend().FilterLogs(context.Background(), query)
 			if err != nil {
-				w.log.Error("Failed to fetch logs", "err", err)
+				log.Error("Failed to fetch logs", "err", err)
 				return
 			}
 
 			// execute the proposal once we find the matching finalized event
 			for _, evt := range evts {
-				out, _ := parsed.Unpack("ProposalEvent", evt.Data)
+				out, _ := bridgeABI.Unpack("ProposalEvent", evt.Data)
 				sourceId := abi.ConvertType(out[0], new(uint8)).(*uint8)
 				depositNonce := abi.ConvertType(out[1], new(uint64)).(*uint64)
 				status := abi.ConvertType(out[2], new(uint8)).(*uint8)
@@ -238,22 +313,43 @@ func (w *writer) watchThenExecute(m msg.Message, latestBlock *big.Int) {
 				if uint8(m.Source) == *sourceId &&
 					m.DepositNonce.Big().Uint64() == *depositNonce &&
 					utils.IsFinalized(*status) {
-					w.log.Info("Got event with status passed", "src", *sourceId, "nonce", *depositNonce, "rId", m.ResourceId.Hex())
+					log.Info("Got event with status passed", "src", *sourceId, "nonce", *depositNonce, "rId", m.ResourceId.Hex())
+					w.executeProposal(m, data, dataHash)
 					return
 				} else {
-					w.log.Trace("Ignoring event", "src", sourceId, "nonce", depositNonce)
+					log.Trace("Ignoring event", "src", sourceId, "nonce", depositNonce)
 				}
 			}
-			w.log.Trace("No finalization event found in current block", "block", latestBlock, "src", m.Source, "nonce", m.DepositNonce)
+			log.Trace("No finalization event found in current block", "block", latestBlock, "src", m.Source, "nonce", m.DepositNonce)
 			latestBlock = latestBlock.Add(latestBlock, big.NewInt(1))
 		}
 	}
 	log.Warn("Block watch limit exceeded, skipping execution", "source", m.Source, "dest", m.Destination, "nonce", m.DepositNonce)
+	w.setMessageState(m, msgqueue.Failed)
+}
+
+// estimateGasLimit returns the gas limit for calling method on the bridge contract with args to resolve m. The configured gas
+// limit is used if the call cannot be estimated.
+func (w *writer) estimateGasLimit(m msg.Message, method string, args ...interface{}) uint64 {
+	log := w.messageLog(m)
+	input, err := bridgeABI.Pack(method, args...)
+	if err != nil {
+		log.Warn("Failed to pack call for gas estimation, using the configured gas limit", "method", method, "err", err)
+		return w.cfg.gasLimit.Uint64()
+	}
+
+	gasLimit, err := w.conn.EstimateGasLimit(w.cfg.bridgeContract, input)
+	if err != nil {
+		log.Warn("Gas estimation failed, using the configured gas limit", "method", method, "gasLimit", gasLimit, "err", err)
+	}
+	return gasLimit
 }
 
 // voteProposal submits a vote proposal
 // a vote proposal will try to be submitted up to the TxRetryLimit times
 func (w *writer) voteProposal(m msg.Message, data []byte, dataHash [32]byte) {
+	log := w.messageLog(m)
+	w.lifecycle.ProposalCreated(m)
 	for i := 0; i < TxRetryLimit; i++ {
 		select {
 		case <-w.stop:
@@ -261,13 +357,14 @@ func (w *writer) voteProposal(m msg.Message, data []byte, dataHash [32]byte) {
 		default:
 			err := w.conn.LockAndUpdateOpts()
 			if err != nil {
-				w.log.Error("Failed to update tx opts", "err", err)
+				log.Error("Failed to update tx opts", "err", err)
 				continue
 			}
 			// These store the gas limit and price before a transaction is sent for logging in case of a failure
 			// This declaration is necessary as tx will be nil in the case of an error when sending VoteProposal()
 			// We must also declare variables instead of using w.conn.Opts() directly as the opts are currently locked
 			// here but for all the logging after line 272 the w.conn.Opts() is unlocked and could be changed by another process
+			w.conn.Opts().GasLimit = w.estimateGasLimit(m, "voteProposal", uint8(m.Destination), uint8(m.Source), uint64(m.DepositNonce), m.ResourceId, data)
 			gasLimit := w.conn.Opts().GasLimit
 			gasPrice := w.conn.Opts().GasPrice
 
@@ -283,32 +380,149 @@ func (w *writer) voteProposal(m msg.Message, data []byte, dataHash [32]byte) {
 			w.conn.UnlockOpts()
 
 			if err == nil {
-				w.log.Info("Submitted proposal vote", "tx", tx.Hash(), "src", m.Source, "depositNonce", m.DepositNonce, "gasPrice", tx.GasPrice().String())
-				if w.metrics != nil {
-					w.metrics.VotesSubmitted.Inc()
+				log.Info("Submitted proposal vote", "tx", tx.Hash(), "src", m.Source, "depositNonce", m.DepositNonce, "gasPrice", tx.GasPrice().String())
+				w.setMessageState(m, msgqueue.Voted)
+				mined, err := w.confirmTx(m, tx, chains.VoteTx, func() bool { return w.proposalIsComplete(m, dataHash) })
+				if err == nil {
+					if mined {
+						w.lifecycle.Voted(m, chains.ResultMined)
+						if w.metrics != nil {
+							w.metrics.VotesSubmitted.Inc()
+						}
+					}
+					return
+				} else if errors.Is(err, errWriterStopped) {
+					return
+				}
+				log.Warn("Vote was not mined successfully, will retry", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce, "err", err)
+				w.setMessageState(m, msgqueue.Received)
+				time.Sleep(TxRetryInterval)
+
+				// A vote that timed out may still have been mined
+				if w.hasVoted(m, dataHash) {
+					log.Info("Relayer has voted on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+					w.setMessageState(m, msgqueue.Voted)
+					return
 				}
-				return
 			} else if err.Error() == ErrNonceTooLow.Error() || err.Error() == ErrTxUnderpriced.Error() {
-				w.log.Debug("Nonce too low, will retry")
+				log.Debug("Nonce too low, will retry")
 				time.Sleep(TxRetryInterval)
 			} else {
-				w.log.Warn("Voting failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce, "gasLimit", gasLimit, "gasPrice", gasPrice, "err", err)
+				log.Warn("Voting failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce, "gasLimit", gasLimit, "gasPrice", gasPrice, "err", err)
 				time.Sleep(TxRetryInterval)
 			}
 
 			// Verify proposal is still open for voting, otherwise no need to retry
-			if w.proposalIsComplete(m.Source, m.DepositNonce, dataHash) {
-				w.log.Info("Proposal voting complete on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+			if w.proposalIsComplete(m, dataHash) {
+				log.Info("Proposal voting complete on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
 				return
 			}
 		}
 	}
-	w.log.Error("Submission of Vote transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	log.Error("Submission of Vote transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	w.lifecycle.Voted(m, chains.ResultFailed)
+	w.setMessageState(m, msgqueue.Failed)
 	w.sysErr <- ErrFatalTx
 }
 
-// executeProposal executes the proposal
+// confirmTx waits for tx or one of its replacements to be mined and checks its receipt. Returns whether a
+// transaction was mined, which is not the case if complete is true first, and ErrTxReverted with the decoded revert
+// reason if it failed on chain.
+func (w *writer) confirmTx(m msg.Message, tx *types.Transaction, txType string, complete func() bool) (bool, error) {
+	log := w.messageLog(m)
+	mined, receipt, err := w.waitMined(m, tx, complete)
+	if err != nil || receipt == nil {
+		return false, err
+	}
+
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		log.Info("Transaction mined", "type", txType, "tx", mined.Hash(), "block", receipt.BlockNumber, "gasUsed", receipt.GasUsed, "src", m.Source, "nonce", m.DepositNonce)
+		if w.relayerMetrics != nil {
+			w.relayerMetrics.TxsMined.WithLabelValues(txType).Inc()
+		}
+		return true, nil
+	}
+
+	reason := w.conn.RevertReason(mined, receipt)
+	log.Error("Transaction reverted", "type", txType, "tx", mined.Hash(), "block", receipt.BlockNumber, "gasUsed", receipt.GasUsed, "gasLimit", mined.Gas(), "reason", reason, "src", m.Source, "nonce", m.DepositNonce)
+	if w.relayerMetrics != nil {
+		w.relayerMetrics.TxsReverted.WithLabelValues(txType).Inc()
+	}
+	return true, fmt.Errorf("%w: %s", ErrTxReverted, reason)
+}
+
+// waitMined waits until tx or one of its replacements is mined and returns the mined transaction with its receipt.
+// Whenever the latest transaction has been pending for longer than the configured timeout it is replaced with higher
+// fees, unless complete reports the transaction is no longer needed, in which case nil is returned. Returns
+// ErrReceiptTimeout if nothing is mined within the receipt timeout.
+func (w *writer) waitMined(m msg.Message, tx *types.Transaction, complete func() bool) (*types.Transaction, *types.Receipt, error) {
+	log := w.messageLog(m)
+	submitted := map[common.Hash]*types.Transaction{tx.Hash(): tx}
+	start := time.Now()
+	lastSubmission := start
+	for {
+		select {
+		case <-w.stop:
+			return nil, nil, errWriterStopped
+		case <-time.After(TxReceiptPollInterval):
+		}
+
+		// Any of the submitted transactions may be the one that is mined
+		for hash, sent := range submitted {
+			receipt, err := w.conn.TransactionReceipt(context.Background(), hash)
+			if err == nil {
+				return sent, receipt, nil
+			} else if !errors.Is(err, eth.NotFound) {
+				log.Warn("Failed to fetch transaction receipt", "tx", hash, "err", err)
+			}
+		}
+
+		if time.Since(start) >= w.cfg.receiptTimeout {
+			return nil, nil, ErrReceiptTimeout
+		}
+		if time.Since(lastSubmission) < w.cfg.txTimeout {
+			continue
+		}
+		if complete() {
+			log.Info("Proposal progressed without pending transaction", "tx", tx.Hash(), "src", m.Source, "nonce", m.DepositNonce)
+			return nil, nil, nil
+		}
+
+		lastSubmission = time.Now()
+		replacement, err := w.conn.ReplaceTx(tx)
+		if errors.Is(err, connection.ErrMaxGasPrice) {
+			log.Warn("Transaction pending at maximum gas price", "tx", tx.Hash(), "nonce", tx.Nonce(), "src", m.Source, "depositNonce", m.DepositNonce)
+			continue
+		} else if err != nil {
+			// The transaction may have been mined in the meantime, which is detected on the next poll
+			log.Warn("Failed to replace pending transaction", "tx", tx.Hash(), "nonce", tx.Nonce(), "err", err)
+			continue
+		}
+
+		log.Info("Replaced pending transaction with higher fees", "old", tx.Hash(), "tx", replacement.Hash(), "nonce", tx.Nonce(),
+			"gasPrice", replacement.GasPrice(), "gasTipCap", replacement.GasTipCap(), "gasFeeCap", replacement.GasFeeCap(), "src", m.Source, "depositNonce", m.DepositNonce)
+		if w.relayerMetrics != nil {
+			w.relayerMetrics.TxReplacements.Inc()
+		}
+		tx = replacement
+		submitted[tx.Hash()] = tx
+	}
+}
+
+// executeProposal executes the proposal, unless the writer is configured to only vote
 func (w *writer) executeProposal(m msg.Message, data []byte, dataHash [32]byte) {
+	log := w.messageLog(m)
+	if w.cfg.voteOnly {
+		log.Info("Proposal passed, execution disabled", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+		return
+	}
+	done := w.drainer.Track("execute", m)
+	if done == nil {
+		log.Warn("Writer is shutting down, not executing proposal", "src", m.Source, "nonce", m.DepositNonce)
+		return
+	}
+	defer done()
+
 	for i := 0; i < TxRetryLimit; i++ {
 		select {
 		case <-w.stop:
@@ -316,11 +530,12 @@ func (w *writer) executeProposal(m msg.Message, data []byte, dataHash [32]byte)
 		default:
 			err := w.conn.LockAndUpdateOpts()
 			if err != nil {
-				w.log.Error("Failed to update nonce", "err", err)
+				log.Error("Failed to update nonce", "err", err)
 				return
 			}
 			// These store the gas limit and price before a transaction is sent for logging in case of a failure
 			// This is necessary as tx will be nil in the case of an error when sending VoteProposal()
+			w.conn.Opts().GasLimit = w.estimateGasLimit(m, "executeProposal", uint8(m.Destination), uint8(m.Source), uint64(m.DepositNonce), data, m.ResourceId, true)
 			gasLimit := w.conn.Opts().GasLimit
 			gasPrice := w.conn.Opts().GasPrice
 
@@ -336,24 +551,38 @@ func (w *writer) executeProposal(m msg.Message, data []byte, dataHash [32]byte)
 			w.conn.UnlockOpts()
 
 			if err == nil {
-				w.log.Info("Submitted proposal execution", "tx", tx.Hash(), "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "gasPrice", tx.GasPrice().String())
-				return
+				log.Info("Submitted proposal execution", "tx", tx.Hash(), "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "gasPrice", tx.GasPrice().String())
+				mined, err := w.confirmTx(m, tx, chains.ExecuteTx, func() bool { return w.proposalIsFinalized(m, dataHash) })
+				if err == nil {
+					if mined {
+						w.lifecycle.Executed(m, chains.ResultMined)
+					}
+					w.setMessageState(m, msgqueue.Executed)
+					return
+				} else if errors.Is(err, errWriterStopped) {
+					return
+				}
+				log.Warn("Execution was not mined successfully, will retry", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce, "err", err)
+				time.Sleep(TxRetryInterval)
 			} else if err.Error() == ErrNonceTooLow.Error() || err.Error() == ErrTxUnderpriced.Error() {
-				w.log.Error("Nonce too low, will retry")
+				log.Error("Nonce too low, will retry")
 				time.Sleep(TxRetryInterval)
 			} else {
-				w.log.Warn("Execution failed, proposal may already be complete", "gasLimit", gasLimit, "gasPrice", gasPrice, "err", err)
+				log.Warn("Execution failed, proposal may already be complete", "gasLimit", gasLimit, "gasPrice", gasPrice, "err", err)
 				time.Sleep(TxRetryInterval)
 			}
 
 			// Verify proposal is still open for execution, tx will fail if we aren't the first to execute,
 			// but there is no need to retry
-			if w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash) {
-				w.log.Info("Proposal finalized on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+			if w.proposalIsFinalized(m, dataHash) {
+				log.Info("Proposal finalized on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+				w.setMessageState(m, w.finalizedState(m, dataHash))
 				return
 			}
 		}
 	}
-	w.log.Error("Submission of Execute transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	log.Error("Submission of Execute transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	w.lifecycle.Executed(m, chains.ResultFailed)
+	w.setMessageState(m, msgqueue.Failed)
 	w.sysErr <- ErrFatalTx
 }
@@ -272,10 +272,10 @@ func TestDuplicateMessage(t *testing.T) {
 	}
 
 	// Ensure the votes are recorded
-	if !writerA.hasVoted(m.Source, m.DepositNonce, dataHash) {
+	if !writerA.hasVoted(m, dataHash) {
 		t.Fatal("Relayer vote not found on chain")
 	}
-	if !writerB.hasVoted(m.Source, m.DepositNonce, dataHash) {
+	if !writerB.hasVoted(m, dataHash) {
 		t.Fatal("Relayer vote not found on chain")
 	}
 
@@ -298,3 +298,34 @@ func TestDuplicateMessage(t *testing.T) {
 	}
 
 }
+
+func TestWriterDrain(t *testing.T) {
+	w := NewWriter(nil, aliceTestConfig, TestLogger, make(chan int), nil, nil)
+	m := msg.NewFungibleTransfer(1, 0, 1, big.NewInt(10), msg.ResourceId{}, []byte{})
+
+	done := w.drainer.Track("execute", m)
+	drained := make(chan struct{})
+	go func() {
+		w.drainer.Drain(time.Second * 5)
+		close(drained)
+	}()
+
+	// New messages are refused while draining
+	<-w.drainer.Draining()
+	if w.ResolveMessage(m) {
+		t.Fatal("draining writer resolved a message")
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned with work pending")
+	default:
+	}
+
+	done()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return after pending work completed")
+	}
+}
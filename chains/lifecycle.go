@@ -0,0 +1,162 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LifecycleRetention is how long the deposit time of a message is kept to measure its transfer latency
+var LifecycleRetention = 24 * time.Hour
+
+// Results of the vote and execution lifecycle metrics
+const (
+	ResultMined  = "mined"
+	ResultFailed = "failed"
+)
+
+// TransferLabel returns the transfer type label of the lifecycle metrics
+func TransferLabel(t msg.TransferType) string {
+	switch t {
+	case msg.FungibleTransfer:
+		return "fungible"
+	case msg.NonFungibleTransfer:
+		return "non-fungible"
+	case msg.GenericTransfer:
+		return "generic"
+	default:
+		return "unknown"
+	}
+}
+
+type messageKey struct {
+	src, dst msg.ChainId
+	nonce    msg.Nonce
+}
+
+// LifecycleMetrics count messages at each step from deposit to execution, labelled by source chain, destination
+// chain and transfer type. A single instance is shared by all chains so that the latency from the deposit block on
+// the source chain to the execution on the destination chain can be measured. All methods are no-ops on nil.
+type LifecycleMetrics struct {
+	DepositsDetected *prometheus.CounterVec
+	ProposalsCreated *prometheus.CounterVec
+	Votes            *prometheus.CounterVec
+	Executions       *prometheus.CounterVec
+	Latency          *prometheus.HistogramVec
+
+	lock     sync.Mutex
+	deposits map[messageKey]time.Time
+}
+
+var lifecycleLabels = []string{"src", "dst", "type"}
+
+func newLifecycleMetrics() *LifecycleMetrics {
+	return &LifecycleMetrics{
+		DepositsDetected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_deposits_detected",
+			Help: "Number of deposits detected by the listeners",
+		}, lifecycleLabels),
+		ProposalsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_proposals_created",
+			Help: "Number of proposals the writers decided to vote on",
+		}, lifecycleLabels),
+		Votes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_votes",
+			Help: "Number of votes by result",
+		}, []string{"src", "dst", "type", "result"}),
+		Executions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_executions",
+			Help: "Number of proposal executions by result",
+		}, []string{"src", "dst", "type", "result"}),
+		Latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "bridge_transfer_latency_seconds",
+			Help:    "Time from the deposit block on the source chain to the execution on the destination chain",
+			Buckets: prometheus.ExponentialBuckets(30, 2, 12),
+		}, lifecycleLabels),
+		deposits: make(map[messageKey]time.Time),
+	}
+}
+
+// NewLifecycleMetrics creates and registers the lifecycle metrics
+func NewLifecycleMetrics() *LifecycleMetrics {
+	l := newLifecycleMetrics()
+	prometheus.MustRegister(l.DepositsDetected, l.ProposalsCreated, l.Votes, l.Executions, l.Latency)
+	return l
+}
+
+func labelValues(m msg.Message) []string {
+	return []string{strconv.Itoa(int(m.Source)), strconv.Itoa(int(m.Destination)), TransferLabel(m.Type)}
+}
+
+func keyOf(m msg.Message) messageKey {
+	return messageKey{src: m.Source, dst: m.Destination, nonce: m.DepositNonce}
+}
+
+// DepositDetected records a deposit included in a block produced at depositTime
+func (l *LifecycleMetrics) DepositDetected(m msg.Message, depositTime time.Time) {
+	if l == nil {
+		return
+	}
+	l.DepositsDetected.WithLabelValues(labelValues(m)...).Inc()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for k, t := range l.deposits {
+		if time.Since(t) > LifecycleRetention {
+			delete(l.deposits, k)
+		}
+	}
+	l.deposits[keyOf(m)] = depositTime
+}
+
+// ProposalCreated records a proposal the writer is about to vote on
+func (l *LifecycleMetrics) ProposalCreated(m msg.Message) {
+	if l == nil {
+		return
+	}
+	l.ProposalsCreated.WithLabelValues(labelValues(m)...).Inc()
+}
+
+// Voted records the result of a vote
+func (l *LifecycleMetrics) Voted(m msg.Message, result string) {
+	if l == nil {
+		return
+	}
+	l.Votes.WithLabelValues(append(labelValues(m), result)...).Inc()
+	if result == ResultFailed {
+		l.forget(m)
+	}
+}
+
+// Executed records the result of an execution. The latency of a mined execution is observed if the deposit was
+// detected by this relayer.
+func (l *LifecycleMetrics) Executed(m msg.Message, result string) {
+	if l == nil {
+		return
+	}
+	l.Executions.WithLabelValues(append(labelValues(m), result)...).Inc()
+	if result != ResultMined {
+		l.forget(m)
+		return
+	}
+
+	l.lock.Lock()
+	depositTime, ok := l.deposits[keyOf(m)]
+	delete(l.deposits, keyOf(m))
+	l.lock.Unlock()
+	if ok {
+		l.Latency.WithLabelValues(labelValues(m)...).Observe(time.Since(depositTime).Seconds())
+	}
+}
+
+func (l *LifecycleMetrics) forget(m msg.Message) {
+	l.lock.Lock()
+	delete(l.deposits, keyOf(m))
+	l.lock.Unlock()
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLifecycleMetrics(t *testing.T) {
+	l := newLifecycleMetrics()
+	m := msg.Message{Source: 1, Destination: 2, Type: msg.FungibleTransfer, DepositNonce: 7}
+
+	l.DepositDetected(m, time.Now().Add(-time.Minute))
+	l.ProposalCreated(m)
+	l.Voted(m, ResultMined)
+	l.Executed(m, ResultMined)
+
+	if v := testutil.ToFloat64(l.DepositsDetected.WithLabelValues("1", "2", "fungible")); v != 1 {
+		t.Fatalf("expected 1 deposit, got %v", v)
+	}
+	if v := testutil.ToFloat64(l.ProposalsCreated.WithLabelValues("1", "2", "fungible")); v != 1 {
+		t.Fatalf("expected 1 proposal, got %v", v)
+	}
+	if v := testutil.ToFloat64(l.Votes.WithLabelValues("1", "2", "fungible", ResultMined)); v != 1 {
+		t.Fatalf("expected 1 mined vote, got %v", v)
+	}
+	if v := testutil.ToFloat64(l.Executions.WithLabelValues("1", "2", "fungible", ResultMined)); v != 1 {
+		t.Fatalf("expected 1 mined execution, got %v", v)
+	}
+	if n := testutil.CollectAndCount(l.Latency); n != 1 {
+		t.Fatalf("expected latency for 1 label set, got %d", n)
+	}
+	if len(l.deposits) != 0 {
+		t.Fatal("expected deposit to be forgotten after execution")
+	}
+
+	// A failed vote forgets the deposit, so no latency is observed for a later execution
+	nft := msg.Message{Source: 1, Destination: 2, Type: msg.NonFungibleTransfer, DepositNonce: 8}
+	l.DepositDetected(nft, time.Now())
+	l.Voted(nft, ResultFailed)
+	l.Executed(nft, ResultMined)
+	if n := testutil.CollectAndCount(l.Latency); n != 1 {
+		t.Fatalf("expected no latency for the failed vote, got %d label sets", n)
+	}
+
+	// Deposits older than the retention are pruned
+	l.DepositDetected(msg.Message{Source: 3, Destination: 2, Type: msg.GenericTransfer, DepositNonce: 1}, time.Now().Add(-2*LifecycleRetention))
+	l.DepositDetected(msg.Message{Source: 3, Destination: 2, Type: msg.GenericTransfer, DepositNonce: 2}, time.Now())
+	if len(l.deposits) != 1 {
+		t.Fatalf("expected stale deposit to be pruned, have %d", len(l.deposits))
+	}
+
+	// Nil metrics are ignored
+	var none *LifecycleMetrics
+	none.DepositDetected(m, time.Now())
+	none.ProposalCreated(m)
+	none.Voted(m, ResultMined)
+	none.Executed(m, ResultFailed)
+}
@@ -0,0 +1,253 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+The limits package checks the fungible transfers of a chain against per-resource limits before they are routed.
+
+Each resource may limit the amount of a single transfer, the total amount transferred within a rolling window, and
+the total amount transferred to a single recipient within the window. Transfers that would exceed a limit are not
+routed, they are held in a durable quarantine until an operator releases or discards them.
+*/
+package limits
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+)
+
+// DefaultWindow is the rolling window of the window and recipient limits if none is configured
+const DefaultWindow = time.Hour * 24
+
+// Config is the limit of a resource as given in the config file. Amounts are integers in the smallest unit of the
+// token as routed, that is in the destination decimals if decimals are configured. Empty amounts are unlimited.
+type Config struct {
+	MaxAmount       string `json:"maxAmount,omitempty" yaml:"maxAmount,omitempty" toml:"maxAmount"`
+	WindowAmount    string `json:"windowAmount,omitempty" yaml:"windowAmount,omitempty" toml:"windowAmount"`
+	RecipientAmount string `json:"recipientAmount,omitempty" yaml:"recipientAmount,omitempty" toml:"recipientAmount"`
+	Window          string `json:"window,omitempty" yaml:"window,omitempty" toml:"window"`
+}
+
+// Limit is the limit of the transfers of a resource. Nil amounts are unlimited.
+type Limit struct {
+	MaxAmount       *big.Int // Amount of a single transfer
+	WindowAmount    *big.Int // Total amount of transfers within the window
+	RecipientAmount *big.Int // Total amount of transfers to a single recipient within the window
+	Window          time.Duration
+}
+
+// Limits are the limits of a chain's transfers by resource ID
+type Limits map[msg.ResourceId]Limit
+
+// Parse parses the limits of a chain's config, keyed by hex encoded resource ID
+func Parse(cfg map[string]Config) (Limits, error) {
+	res := make(Limits)
+	for id, c := range cfg {
+		rId, err := parseResourceId(id)
+		if err != nil {
+			return nil, err
+		}
+
+		var l Limit
+		for _, a := range []struct {
+			name string
+			val  string
+			dst  **big.Int
+		}{
+			{"maxAmount", c.MaxAmount, &l.MaxAmount},
+			{"windowAmount", c.WindowAmount, &l.WindowAmount},
+			{"recipientAmount", c.RecipientAmount, &l.RecipientAmount},
+		} {
+			if a.val == "" {
+				continue
+			}
+			amount, ok := new(big.Int).SetString(a.val, 10)
+			if !ok || amount.Sign() < 0 {
+				return nil, fmt.Errorf("invalid %s %q for resource %s", a.name, a.val, id)
+			}
+			*a.dst = amount
+		}
+
+		l.Window = DefaultWindow
+		if c.Window != "" {
+			l.Window, err = time.ParseDuration(c.Window)
+			if err != nil || l.Window <= 0 {
+				return nil, fmt.Errorf("invalid window %q for resource %s", c.Window, id)
+			}
+		}
+		res[rId] = l
+	}
+	return res, nil
+}
+
+func parseResourceId(id string) (msg.ResourceId, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+	if err != nil || len(bz) != len(msg.ResourceId{}) {
+		return msg.ResourceId{}, fmt.Errorf("invalid resource ID %q in limits", id)
+	}
+	return msg.ResourceIdFromSlice(bz), nil
+}
+
+// transfer is a routed transfer counted towards the window limits
+type transfer struct {
+	key       string
+	recipient string
+	amount    *big.Int
+	time      time.Time
+}
+
+// Gate is a chains.Router that routes transfers within the limits and quarantines the others. It is safe for
+// concurrent use.
+type Gate struct {
+	limits     Limits
+	router     chains.Router
+	quarantine *Quarantine
+	window     *Window // Transfers routed within the window
+	log        log15.Logger
+	lock       sync.Mutex
+	now        func() time.Time
+}
+
+// NewGate creates a Gate checking transfers against limits. SetRouter must be called before messages are sent.
+func NewGate(limits Limits, q *Quarantine, w *Window, log log15.Logger) *Gate {
+	return &Gate{
+		limits:     limits,
+		quarantine: q,
+		window:     w,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+// SetRouter sets the router transfers within the limits and released transfers are sent to
+func (g *Gate) SetRouter(r chains.Router) {
+	g.router = r
+}
+
+// Send routes m if it is within the limits, otherwise m is quarantined. Messages that are already quarantined, such
+// as deposits routed again after a re-scan, stay quarantined.
+func (g *Gate) Send(m msg.Message) error {
+	if _, ok := g.quarantine.Find(m.Destination, m.DepositNonce); ok {
+		chains.MessageLogger(g.log, m).Warn("Transfer is quarantined, not routing")
+		return nil
+	}
+
+	// The lock is held until a routed transfer is counted, so concurrent transfers are checked against it
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	t, reason := g.check(m)
+	if reason != "" {
+		chains.MessageLogger(g.log, m).Warn("Quarantined transfer", "reason", reason, "rId", m.ResourceId.Hex())
+		return g.quarantine.Add(m, reason)
+	}
+	err := g.router.Send(m)
+	if err != nil {
+		return err
+	}
+	if t != nil {
+		g.count(m.ResourceId, *t)
+	}
+	return nil
+}
+
+// check returns why m exceeds the limits, or an empty string if it is within them. If m is within the limits and
+// must be counted towards the window limits once routed, its transfer is returned. The caller must hold the lock.
+func (g *Gate) check(m msg.Message) (*transfer, string) {
+	l, ok := g.limits[m.ResourceId]
+	if !ok || m.Type != msg.FungibleTransfer {
+		return nil, ""
+	}
+
+	t := newTransfer(m, g.now())
+	routed := g.window.prune(m.ResourceId, g.now().Add(-l.Window))
+	var total, recipient big.Int
+	for _, r := range routed {
+		// Deposits routed again after a re-scan were already counted
+		if r.key == t.key {
+			return nil, ""
+		}
+		total.Add(&total, r.amount)
+		if r.recipient == t.recipient {
+			recipient.Add(&recipient, r.amount)
+		}
+	}
+	total.Add(&total, t.amount)
+	recipient.Add(&recipient, t.amount)
+
+	switch {
+	case l.MaxAmount != nil && t.amount.Cmp(l.MaxAmount) > 0:
+		return nil, fmt.Sprintf("amount %s exceeds the maximum of %s per transfer", t.amount, l.MaxAmount)
+	case l.WindowAmount != nil && total.Cmp(l.WindowAmount) > 0:
+		return nil, fmt.Sprintf("total of %s within %s exceeds the maximum of %s", &total, l.Window, l.WindowAmount)
+	case l.RecipientAmount != nil && recipient.Cmp(l.RecipientAmount) > 0:
+		return nil, fmt.Sprintf("total of %s to recipient %s within %s exceeds the maximum of %s", &recipient, t.recipient, l.Window, l.RecipientAmount)
+	}
+	return &t, ""
+}
+
+// count adds t to the window of rId. The caller must hold the lock.
+func (g *Gate) count(rId msg.ResourceId, t transfer) {
+	err := g.window.add(rId, t)
+	if err != nil {
+		g.log.Error("Failed to persist transfer limit window", "rId", rId.Hex(), "err", err)
+	}
+}
+
+func newTransfer(m msg.Message, now time.Time) transfer {
+	return transfer{
+		key:       chains.CorrelationID(m.Source, m.Destination, m.DepositNonce),
+		recipient: "0x" + hex.EncodeToString(m.Payload[1].([]byte)),
+		amount:    new(big.Int).SetBytes(m.Payload[0].([]byte)),
+		time:      now,
+	}
+}
+
+// Quarantined returns the quarantined transfers, ordered by destination and deposit nonce
+func (g *Gate) Quarantined() []Entry {
+	return g.quarantine.Entries()
+}
+
+// Release routes the quarantined transfer to dst with the given deposit nonce. The transfer is counted towards the
+// window limits but is not checked against them. It stays quarantined if it cannot be routed.
+func (g *Gate) Release(dst msg.ChainId, nonce msg.Nonce) (Entry, error) {
+	e, ok := g.quarantine.Find(dst, nonce)
+	if !ok {
+		return Entry{}, fmt.Errorf("no quarantined transfer to chain %d with nonce %d", dst, nonce)
+	}
+
+	err := g.router.Send(e.Message)
+	if err != nil {
+		return Entry{}, fmt.Errorf("unable to route released transfer: %w", err)
+	}
+	chains.MessageLogger(g.log, e.Message).Info("Released quarantined transfer", "reason", e.Reason)
+
+	if l, ok := g.limits[e.Message.ResourceId]; ok && e.Message.Type == msg.FungibleTransfer {
+		g.lock.Lock()
+		g.window.prune(e.Message.ResourceId, g.now().Add(-l.Window))
+		g.count(e.Message.ResourceId, newTransfer(e.Message, g.now()))
+		g.lock.Unlock()
+	}
+	return e, g.quarantine.Remove(dst, nonce)
+}
+
+// Discard removes the quarantined transfer to dst with the given deposit nonce without routing it
+func (g *Gate) Discard(dst msg.ChainId, nonce msg.Nonce) (Entry, error) {
+	e, ok := g.quarantine.Find(dst, nonce)
+	if !ok {
+		return Entry{}, fmt.Errorf("no quarantined transfer to chain %d with nonce %d", dst, nonce)
+	}
+	err := g.quarantine.Remove(dst, nonce)
+	if err != nil {
+		return Entry{}, err
+	}
+	chains.MessageLogger(g.log, e.Message).Info("Discarded quarantined transfer", "reason", e.Reason)
+	return e, nil
+}
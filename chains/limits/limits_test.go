@@ -0,0 +1,224 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package limits
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+type recordingRouter struct {
+	sent []msg.Message
+}
+
+func (r *recordingRouter) Send(m msg.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+var testResource = msg.ResourceIdFromSlice([]byte{1})
+
+func newTestGate(t *testing.T, dir string, l Limit) (*Gate, *recordingRouter) {
+	q, err := NewQuarantine(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWindow(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGate(Limits{testResource: l}, q, w, log15.New())
+	r := &recordingRouter{}
+	g.SetRouter(r)
+	return g, r
+}
+
+func transferTo(nonce msg.Nonce, amount int64, recipient byte) msg.Message {
+	return msg.NewFungibleTransfer(1, 2, nonce, big.NewInt(amount), testResource, []byte{recipient})
+}
+
+func TestParse(t *testing.T) {
+	limits, err := Parse(map[string]Config{
+		"0x" + testResource.Hex(): {MaxAmount: "100", WindowAmount: "1000", Window: "1h"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Limits{testResource: {MaxAmount: big.NewInt(100), WindowAmount: big.NewInt(1000), Window: time.Hour}}
+	if !reflect.DeepEqual(limits, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, limits)
+	}
+
+	limits, err = Parse(map[string]Config{testResource.Hex(): {RecipientAmount: "5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := limits[testResource]; l.Window != DefaultWindow || l.RecipientAmount.Int64() != 5 || l.MaxAmount != nil {
+		t.Fatalf("unexpected limit %+v", l)
+	}
+
+	for _, cfg := range []map[string]Config{
+		{"0x01": {MaxAmount: "100"}},
+		{testResource.Hex(): {MaxAmount: "1e18"}},
+		{testResource.Hex(): {WindowAmount: "-1"}},
+		{testResource.Hex(): {Window: "0s"}},
+	} {
+		if _, err = Parse(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestGate(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	g, r := newTestGate(t, dir, Limit{MaxAmount: big.NewInt(100), WindowAmount: big.NewInt(250), RecipientAmount: big.NewInt(150), Window: time.Hour})
+	g.now = func() time.Time { return now }
+
+	send := func(m msg.Message) {
+		if err := g.Send(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(transferTo(1, 100, 0xa))
+	send(transferTo(2, 101, 0xb)) // Exceeds the maximum per transfer
+	send(transferTo(3, 60, 0xa))  // Exceeds the recipient limit
+	send(transferTo(4, 100, 0xb))
+	send(transferTo(5, 60, 0xc)) // Exceeds the window limit
+	// Routed again after a re-scan
+	send(transferTo(1, 100, 0xa))
+	send(transferTo(2, 101, 0xb))
+	// Other resources and non-fungible transfers are not limited
+	send(msg.NewFungibleTransfer(1, 2, 6, big.NewInt(1000), msg.ResourceIdFromSlice([]byte{2}), []byte{0xa}))
+	send(msg.NewNonFungibleTransfer(1, 2, 7, testResource, big.NewInt(1000), []byte{0xa}, nil))
+
+	var routed []msg.Nonce
+	for _, m := range r.sent {
+		routed = append(routed, m.DepositNonce)
+	}
+	if !reflect.DeepEqual(routed, []msg.Nonce{1, 4, 1, 6, 7}) {
+		t.Fatalf("unexpected routed nonces %v", routed)
+	}
+
+	var quarantined []msg.Nonce
+	for _, e := range g.Quarantined() {
+		quarantined = append(quarantined, e.Message.DepositNonce)
+	}
+	if !reflect.DeepEqual(quarantined, []msg.Nonce{2, 3, 5}) {
+		t.Fatalf("unexpected quarantined nonces %v", quarantined)
+	}
+
+	// Transfers older than the window no longer count
+	now = now.Add(time.Hour + time.Second)
+	send(transferTo(8, 100, 0xa))
+	if last := r.sent[len(r.sent)-1]; last.DepositNonce != 8 {
+		t.Fatalf("expected transfer to be routed after the window, last routed %d", last.DepositNonce)
+	}
+
+	// The quarantine survives a restart, released transfers are routed and discarded ones are dropped
+	g, r = newTestGate(t, dir, Limit{MaxAmount: big.NewInt(100)})
+	if len(g.Quarantined()) != 3 {
+		t.Fatalf("expected 3 quarantined transfers after restart, got %d", len(g.Quarantined()))
+	}
+	e, err := g.Release(2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.sent) != 1 || !reflect.DeepEqual(r.sent[0], e.Message) || !reflect.DeepEqual(e.Message, transferTo(2, 101, 0xb)) {
+		t.Fatalf("expected released transfer to be routed, got %+v", r.sent)
+	}
+	if _, err = g.Discard(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = g.Release(2, 3); err == nil {
+		t.Fatal("expected error releasing a discarded transfer")
+	}
+	if len(g.Quarantined()) != 1 {
+		t.Fatalf("expected 1 quarantined transfer, got %d", len(g.Quarantined()))
+	}
+}
+
+type failingRouter struct{}
+
+func (failingRouter) Send(m msg.Message) error {
+	return errors.New("router unavailable")
+}
+
+func TestReleaseKeepsTransferOnError(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g, _ := newTestGate(t, dir, Limit{MaxAmount: big.NewInt(100)})
+	if err = g.Send(transferTo(1, 101, 0xa)); err != nil {
+		t.Fatal(err)
+	}
+	g.SetRouter(failingRouter{})
+	if _, err = g.Release(2, 1); err == nil {
+		t.Fatal("expected release to fail")
+	}
+	if len(g.Quarantined()) != 1 {
+		t.Fatal("expected transfer to stay quarantined")
+	}
+}
+
+func TestSendCountsOnlyRoutedTransfers(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g, r := newTestGate(t, dir, Limit{WindowAmount: big.NewInt(100), Window: time.Hour})
+	g.SetRouter(failingRouter{})
+	if err = g.Send(transferTo(1, 100, 0xa)); err == nil {
+		t.Fatal("expected send to fail")
+	}
+
+	// The transfer that was not routed does not count towards the window
+	g.SetRouter(r)
+	if err = g.Send(transferTo(2, 100, 0xa)); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.sent) != 1 || len(g.Quarantined()) != 0 {
+		t.Fatalf("expected 1 routed and no quarantined transfers, got %d and %d", len(r.sent), len(g.Quarantined()))
+	}
+}
+
+func TestWindowSurvivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := Limit{WindowAmount: big.NewInt(150), Window: time.Hour}
+	g, _ := newTestGate(t, dir, l)
+	if err = g.Send(transferTo(1, 100, 0xa)); err != nil {
+		t.Fatal(err)
+	}
+
+	g, r := newTestGate(t, dir, l)
+	if err = g.Send(transferTo(2, 100, 0xb)); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.sent) != 0 || len(g.Quarantined()) != 1 {
+		t.Fatalf("expected transfer exceeding the restored window to be quarantined, routed %d", len(r.sent))
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+)
+
+// Entry is a quarantined transfer
+type Entry struct {
+	Message     msg.Message
+	Reason      string
+	Quarantined time.Time
+}
+
+// Quarantine is a file backed store of the transfers of a chain that exceeded the limits. Transfers are kept until
+// they are removed. It is safe for concurrent use.
+type Quarantine struct {
+	path    string
+	entries map[string]*Entry
+	lock    sync.Mutex
+}
+
+// NewQuarantine opens the quarantine for the given chain and relayer in path, creating it if it does not exist
+func NewQuarantine(path string, chain msg.ChainId, relayer string) (*Quarantine, error) {
+	path, err := msgqueue.FilePath(path, chain, relayer, "quarantine")
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quarantine{
+		path:    path,
+		entries: make(map[string]*Entry),
+	}
+
+	err = q.load()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Add quarantines m. Messages that are already quarantined are left unchanged.
+func (q *Quarantine) Add(m msg.Message, reason string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	k := key(m.Destination, m.DepositNonce)
+	if _, ok := q.entries[k]; ok {
+		return nil
+	}
+	q.entries[k] = &Entry{Message: m, Reason: reason, Quarantined: time.Now()}
+	return q.save()
+}
+
+// Find returns the quarantined transfer to dst with the given deposit nonce, and false if there is none
+func (q *Quarantine) Find(dst msg.ChainId, nonce msg.Nonce) (Entry, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	e, ok := q.entries[key(dst, nonce)]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// Remove removes the quarantined transfer to dst with the given deposit nonce
+func (q *Quarantine) Remove(dst msg.ChainId, nonce msg.Nonce) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.entries, key(dst, nonce))
+	return q.save()
+}
+
+// Entries returns all quarantined transfers, ordered by destination chain and deposit nonce
+func (q *Quarantine) Entries() []Entry {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	res := make([]Entry, 0, len(q.entries))
+	for _, e := range q.entries {
+		res = append(res, *e)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Message.Destination != res[j].Message.Destination {
+			return res[i].Message.Destination < res[j].Message.Destination
+		}
+		return res[i].Message.DepositNonce < res[j].Message.DepositNonce
+	})
+	return res
+}
+
+// key identifies a transfer of the chain, as deposit nonces are counted per destination
+func key(dst msg.ChainId, nonce msg.Nonce) string {
+	return fmt.Sprintf("%d-%d", dst, nonce)
+}
+
+// storedEntry is the on-disk representation of an Entry
+type storedEntry struct {
+	msgqueue.StoredMessage
+	Reason      string    `json:"reason"`
+	Quarantined time.Time `json:"quarantined"`
+}
+
+func (q *Quarantine) load() error {
+	data, err := ioutil.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var stored []storedEntry
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return fmt.Errorf("unable to parse quarantine %s: %w", q.path, err)
+	}
+
+	for _, s := range stored {
+		m := s.Message()
+		q.entries[key(m.Destination, m.DepositNonce)] = &Entry{Message: m, Reason: s.Reason, Quarantined: s.Quarantined}
+	}
+	return nil
+}
+
+func (q *Quarantine) save() error {
+	stored := make([]storedEntry, 0, len(q.entries))
+	for _, e := range q.entries {
+		sm, err := msgqueue.NewStoredMessage(e.Message)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, storedEntry{StoredMessage: sm, Reason: e.Reason, Quarantined: e.Quarantined})
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return msgqueue.WriteFile(q.path, data)
+}
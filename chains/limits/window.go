@@ -0,0 +1,102 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+)
+
+// Window is a file backed record of the transfers of a chain counted towards the window limits, so the limits
+// still apply after a restart. It is not safe for concurrent use, the Gate guards it.
+type Window struct {
+	path      string
+	transfers map[msg.ResourceId][]transfer // Oldest first
+}
+
+// NewWindow opens the window for the given chain and relayer in path, creating it if it does not exist
+func NewWindow(path string, chain msg.ChainId, relayer string) (*Window, error) {
+	path, err := msgqueue.FilePath(path, chain, relayer, "window")
+	if err != nil {
+		return nil, err
+	}
+	w := &Window{path: path, transfers: make(map[msg.ResourceId][]transfer)}
+	err = w.load()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// prune removes the transfers of rId before cutoff and returns the rest
+func (w *Window) prune(rId msg.ResourceId, cutoff time.Time) []transfer {
+	routed := w.transfers[rId]
+	i := 0
+	for i < len(routed) && routed[i].time.Before(cutoff) {
+		i++
+	}
+	w.transfers[rId] = routed[i:]
+	return routed[i:]
+}
+
+// add counts t towards the window of rId
+func (w *Window) add(rId msg.ResourceId, t transfer) error {
+	w.transfers[rId] = append(w.transfers[rId], t)
+	return w.save()
+}
+
+// storedTransfer is the on-disk representation of a transfer
+type storedTransfer struct {
+	ResourceId msg.ResourceId `json:"resourceId"`
+	Key        string         `json:"key"`
+	Recipient  string         `json:"recipient"`
+	Amount     string         `json:"amount"`
+	Time       time.Time      `json:"time"`
+}
+
+func (w *Window) load() error {
+	data, err := ioutil.ReadFile(w.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var stored []storedTransfer
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return fmt.Errorf("unable to parse window %s: %w", w.path, err)
+	}
+
+	for _, s := range stored {
+		amount, ok := new(big.Int).SetString(s.Amount, 10)
+		if !ok {
+			return fmt.Errorf("invalid amount %q in window %s", s.Amount, w.path)
+		}
+		w.transfers[s.ResourceId] = append(w.transfers[s.ResourceId], transfer{key: s.Key, recipient: s.Recipient, amount: amount, time: s.Time})
+	}
+	return nil
+}
+
+func (w *Window) save() error {
+	stored := make([]storedTransfer, 0)
+	for rId, routed := range w.transfers {
+		for _, t := range routed {
+			stored = append(stored, storedTransfer{ResourceId: rId, Key: t.key, Recipient: t.recipient, Amount: t.amount.String(), Time: t.time})
+		}
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return msgqueue.WriteFile(w.path, data)
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+// CorrelationKey is the log key of the correlation ID of a transfer
+const CorrelationKey = "correlationId"
+
+// CorrelationID identifies a transfer in the logs of every chain it passes through. It has the form
+// <source>-<destination>-<nonce>.
+func CorrelationID(src, dst msg.ChainId, nonce msg.Nonce) string {
+	return fmt.Sprintf("%d-%d-%d", src, dst, nonce)
+}
+
+// MessageLogger returns log with the correlation ID of m added to every line
+func MessageLogger(log log15.Logger, m msg.Message) log15.Logger {
+	return log.New(CorrelationKey, CorrelationID(m.Source, m.Destination, m.DepositNonce))
+}
+
+// loggingRouter logs every message sent with its correlation ID
+type loggingRouter struct {
+	router Router
+	log    log15.Logger
+}
+
+// NewLoggingRouter wraps r to log the messages sent through it
+func NewLoggingRouter(r Router, log log15.Logger) Router {
+	return &loggingRouter{router: r, log: log}
+}
+
+func (r *loggingRouter) Send(m msg.Message) error {
+	MessageLogger(r.log, m).Debug("Routing message", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "type", m.Type, "rId", m.ResourceId.Hex())
+	return r.router.Send(m)
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+type recordingRouter struct {
+	sent []msg.Message
+}
+
+func (r *recordingRouter) Send(m msg.Message) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestLoggingRouter(t *testing.T) {
+	var records []*log15.Record
+	log := log15.New()
+	log.SetHandler(log15.FuncHandler(func(r *log15.Record) error {
+		records = append(records, r)
+		return nil
+	}))
+
+	inner := &recordingRouter{}
+	m := msg.Message{Source: 1, Destination: 2, DepositNonce: 42, Type: msg.FungibleTransfer}
+	err := NewLoggingRouter(inner, log).Send(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.sent) != 1 {
+		t.Fatalf("expected message to be routed, got %d", len(inner.sent))
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(records))
+	}
+	ctx := records[0].Ctx
+	if len(ctx) < 2 || ctx[0] != CorrelationKey || ctx[1] != "1-2-42" {
+		t.Fatalf("expected correlation ID 1-2-42 first in the context, got %v", ctx)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are the relayer metrics for a chain that are not covered by the ChainMetrics of chainbridge-utils
+type Metrics struct {
+	ReorgsDetected  prometheus.Counter
+	LastReorgDepth  prometheus.Gauge
+	DepositsReorged prometheus.Counter
+	TxReplacements  prometheus.Counter
+	TxsMined        *prometheus.CounterVec
+	TxsReverted     *prometheus.CounterVec
+}
+
+// Transaction types of the TxsMined and TxsReverted metrics
+const (
+	VoteTx    = "vote"
+	ExecuteTx = "execute"
+)
+
+// NewMetrics creates and registers the metrics for a chain
+func NewMetrics(chain string) *Metrics {
+	m := &Metrics{
+		ReorgsDetected: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: chain + "_reorgs_detected",
+			Help: "Number of chain reorganizations detected by the listener",
+		}),
+		LastReorgDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: chain + "_last_reorg_depth",
+			Help: "Number of blocks replaced by the most recent chain reorganization",
+		}),
+		DepositsReorged: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: chain + "_deposits_reorged",
+			Help: "Number of routed deposits that are no longer on the canonical chain",
+		}),
+		TxReplacements: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: chain + "_tx_replacements",
+			Help: "Number of pending transactions resubmitted with higher fees",
+		}),
+		TxsMined: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: chain + "_txs_mined",
+			Help: "Number of relayer transactions mined successfully",
+		}, []string{"type"}),
+		TxsReverted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: chain + "_txs_reverted",
+			Help: "Number of relayer transactions that reverted on chain",
+		}, []string{"type"}),
+	}
+
+	prometheus.MustRegister(m.ReorgsDetected, m.LastReorgDepth, m.DepositsReorged, m.TxReplacements, m.TxsMined, m.TxsReverted)
+	return m
+}
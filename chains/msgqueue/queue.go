@@ -0,0 +1,328 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+The msgqueue package provides a durable record of the messages a writer has received.
+
+Every message routed to a writer is stored along with its state (received, voted, executed or failed). The queue is
+written to disk on every state change, so messages that were in-flight when the relayer stopped can be replayed by
+the writer on startup.
+*/
+package msgqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+// PathPostfix is appended to the user's home directory when no path is given, matching the blockstore default
+const PathPostfix = ".chainbridge/blockstore"
+
+// FinishedRetention is how long finished entries are kept before being pruned from the queue
+var FinishedRetention = time.Hour * 24
+
+type State string
+
+const (
+	Received State = "received"
+	Voted    State = "voted"
+	Executed State = "executed"
+	Failed   State = "failed"
+)
+
+// Finished returns true if no further action is required for a message in this state
+func (s State) Finished() bool {
+	return s == Executed || s == Failed
+}
+
+type Entry struct {
+	Message msg.Message
+	State   State
+	Updated time.Time
+}
+
+// Queue is a file backed store of messages and their states. It is safe for concurrent use.
+type Queue struct {
+	path          string
+	entries       map[string]*Entry
+	votedFinished bool // Voted entries are finished, see SetVotedFinished
+	lock          sync.Mutex
+}
+
+// FilePath returns the path of the file with the given extension for the chain and relayer in path, creating the
+// directory if it does not exist. The user's home directory is used if path is empty.
+func FilePath(path string, chain msg.ChainId, relayer, ext string) (string, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, PathPostfix)
+	}
+
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(path, fmt.Sprintf("%s-%d.%s", relayer, chain, ext)), nil
+}
+
+// NewQueue opens the queue for the given chain and relayer in path, creating it if it does not exist
+func NewQueue(path string, chain msg.ChainId, relayer string) (*Queue, error) {
+	path, err := FilePath(path, chain, relayer, "queue")
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{
+		path:    path,
+		entries: make(map[string]*Entry),
+	}
+
+	err = q.load()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Add records a newly received message. Messages that are already in the queue are left unchanged.
+// Returns false if the message was already present.
+func (q *Queue) Add(m msg.Message) (bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	k := key(m)
+	if _, ok := q.entries[k]; ok {
+		return false, nil
+	}
+	q.entries[k] = &Entry{Message: m, State: Received, Updated: time.Now()}
+	return true, q.save()
+}
+
+// SetState updates the state of a message, adding it to the queue if it is not present
+func (q *Queue) SetState(m msg.Message, s State) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	k := key(m)
+	e, ok := q.entries[k]
+	if !ok {
+		e = &Entry{Message: m}
+		q.entries[k] = e
+	} else if e.State == s {
+		return nil
+	}
+	e.State = s
+	e.Updated = time.Now()
+	return q.save()
+}
+
+// SetVotedFinished makes Voted a finished state, for writers that never execute proposals themselves, eg. when the
+// destination chain executes them. Voted entries are then pruned like executed and failed ones.
+func (q *Queue) SetVotedFinished() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.votedFinished = true
+}
+
+// Finished returns true if no further action is required by the writer for a message in state s
+func (q *Queue) Finished(s State) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.finished(s)
+}
+
+func (q *Queue) finished(s State) bool {
+	return s.Finished() || (q.votedFinished && s == Voted)
+}
+
+// State returns the current state of a message, and false if it is not in the queue
+func (q *Queue) State(m msg.Message) (State, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	e, ok := q.entries[key(m)]
+	if !ok {
+		return "", false
+	}
+	return e.State, true
+}
+
+// Entries returns all entries in any of the given states, ordered by source chain and deposit nonce
+func (q *Queue) Entries(states ...State) []Entry {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	res := make([]Entry, 0)
+	for _, e := range q.entries {
+		for _, s := range states {
+			if e.State == s {
+				res = append(res, *e)
+				break
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Message.Source != res[j].Message.Source {
+			return res[i].Message.Source < res[j].Message.Source
+		}
+		return res[i].Message.DepositNonce < res[j].Message.DepositNonce
+	})
+	return res
+}
+
+// Find returns the entry for the message from src with the given deposit nonce, and false if there is none
+func (q *Queue) Find(src msg.ChainId, nonce msg.Nonce) (Entry, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, e := range q.entries {
+		if e.Message.Source == src && e.Message.DepositNonce == nonce {
+			return *e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func key(m msg.Message) string {
+	return fmt.Sprintf("%d-%d-%d", m.Source, m.Destination, m.DepositNonce)
+}
+
+// StoredMessage is the on-disk representation of a message. All message payloads consist of byte slices, which
+// would not survive a round trip through json as interface{} values.
+type StoredMessage struct {
+	Source       msg.ChainId      `json:"source"`
+	Destination  msg.ChainId      `json:"destination"`
+	Type         msg.TransferType `json:"type"`
+	DepositNonce msg.Nonce        `json:"depositNonce"`
+	ResourceId   msg.ResourceId   `json:"resourceId"`
+	Payload      [][]byte         `json:"payload"`
+}
+
+// NewStoredMessage converts m to its on-disk representation
+func NewStoredMessage(m msg.Message) (StoredMessage, error) {
+	payload := make([][]byte, len(m.Payload))
+	for i, p := range m.Payload {
+		bz, ok := p.([]byte)
+		if !ok {
+			return StoredMessage{}, fmt.Errorf("unsupported payload type %T in message %s", p, key(m))
+		}
+		payload[i] = bz
+	}
+	return StoredMessage{
+		Source:       m.Source,
+		Destination:  m.Destination,
+		Type:         m.Type,
+		DepositNonce: m.DepositNonce,
+		ResourceId:   m.ResourceId,
+		Payload:      payload,
+	}, nil
+}
+
+// Message returns the message s represents
+func (s StoredMessage) Message() msg.Message {
+	payload := make([]interface{}, len(s.Payload))
+	for i, p := range s.Payload {
+		payload[i] = p
+	}
+	return msg.Message{
+		Source:       s.Source,
+		Destination:  s.Destination,
+		Type:         s.Type,
+		DepositNonce: s.DepositNonce,
+		ResourceId:   s.ResourceId,
+		Payload:      payload,
+	}
+}
+
+// storedEntry is the on-disk representation of an Entry
+type storedEntry struct {
+	StoredMessage
+	State   State     `json:"state"`
+	Updated time.Time `json:"updated"`
+}
+
+func (q *Queue) load() error {
+	data, err := ioutil.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var stored []storedEntry
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return fmt.Errorf("unable to parse queue %s: %w", q.path, err)
+	}
+
+	for _, s := range stored {
+		m := s.Message()
+		q.entries[key(m)] = &Entry{Message: m, State: s.State, Updated: s.Updated}
+	}
+	return nil
+}
+
+// save writes the queue to disk, pruning any finished entries older than FinishedRetention
+func (q *Queue) save() error {
+	stored := make([]storedEntry, 0, len(q.entries))
+	for k, e := range q.entries {
+		if q.finished(e.State) && time.Since(e.Updated) > FinishedRetention {
+			delete(q.entries, k)
+			continue
+		}
+
+		sm, err := NewStoredMessage(e.Message)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, storedEntry{StoredMessage: sm, State: e.State, Updated: e.Updated})
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return WriteFile(q.path, data)
+}
+
+// WriteFile replaces the file at path with data. The data is written to a temporary file and synced before it is
+// renamed, and the directory is synced after, so neither a crash nor a power loss can leave a partially written file.
+func WriteFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(filepath.Clean(tmp), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
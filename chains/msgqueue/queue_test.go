@@ -0,0 +1,163 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package msgqueue
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+func newTestQueue(t *testing.T, dir string) *Queue {
+	q, err := NewQueue(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestQueuePersistsState(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "msgqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fungible := msg.NewFungibleTransfer(0, 1, 1, big.NewInt(10), msg.ResourceId{1}, []byte{0xab})
+	nonFungible := msg.NewNonFungibleTransfer(0, 1, 2, msg.ResourceId{2}, big.NewInt(99), []byte{0xcd}, []byte("metadata"))
+	generic := msg.NewGenericTransfer(0, 1, 3, msg.ResourceId{3}, []byte("hash"))
+
+	q := newTestQueue(t, dir)
+	for _, m := range []msg.Message{fungible, nonFungible, generic} {
+		added, err := q.Add(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !added {
+			t.Fatalf("expected nonce %d to be added", m.DepositNonce)
+		}
+	}
+
+	added, err := q.Add(fungible)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Fatal("duplicate message should not be added")
+	}
+
+	err = q.SetState(nonFungible, Voted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = q.SetState(generic, Executed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen the queue from disk
+	q = newTestQueue(t, dir)
+
+	pending := q.Entries(Received, Voted)
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending entries, got %d", len(pending))
+	}
+	if !reflect.DeepEqual(pending[0].Message, fungible) || pending[0].State != Received {
+		t.Fatalf("unexpected entry: %#v", pending[0])
+	}
+	if !reflect.DeepEqual(pending[1].Message, nonFungible) || pending[1].State != Voted {
+		t.Fatalf("unexpected entry: %#v", pending[1])
+	}
+
+	state, ok := q.State(generic)
+	if !ok || state != Executed {
+		t.Fatalf("expected executed state, got %s", state)
+	}
+}
+
+func TestQueuePrunesFinished(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "msgqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	retention := FinishedRetention
+	FinishedRetention = time.Millisecond
+	defer func() { FinishedRetention = retention }()
+
+	q := newTestQueue(t, dir)
+	executed := msg.NewGenericTransfer(0, 1, 1, msg.ResourceId{1}, []byte("a"))
+	pending := msg.NewGenericTransfer(0, 1, 2, msg.ResourceId{1}, []byte("b"))
+	voted := msg.NewGenericTransfer(0, 1, 3, msg.ResourceId{1}, []byte("c"))
+
+	err = q.SetState(executed, Executed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = q.SetState(voted, Voted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 5)
+
+	// Any write prunes expired entries
+	_, err = q.Add(pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := q.State(executed); ok {
+		t.Fatal("expected executed entry to be pruned")
+	}
+	if _, ok := q.State(pending); !ok {
+		t.Fatal("expected pending entry to be kept")
+	}
+	// Voted proposals may still need to be executed
+	if _, ok := q.State(voted); !ok {
+		t.Fatal("expected voted entry to be kept")
+	}
+}
+
+func TestQueuePrunesVotedIfFinished(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "msgqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	retention := FinishedRetention
+	FinishedRetention = time.Millisecond
+	defer func() { FinishedRetention = retention }()
+
+	q := newTestQueue(t, dir)
+	q.SetVotedFinished()
+	if !q.Finished(Voted) || q.Finished(Received) {
+		t.Fatal("expected only voted to become finished")
+	}
+
+	voted := msg.NewGenericTransfer(0, 1, 1, msg.ResourceId{1}, []byte("a"))
+	pending := msg.NewGenericTransfer(0, 1, 2, msg.ResourceId{1}, []byte("b"))
+	err = q.SetState(voted, Voted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 5)
+
+	_, err = q.Add(pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.State(voted); ok {
+		t.Fatal("expected voted entry to be pruned")
+	}
+	if _, ok := q.State(pending); !ok {
+		t.Fatal("expected pending entry to be kept")
+	}
+}
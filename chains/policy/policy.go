@@ -0,0 +1,224 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+The policy package decides which messages the writers may vote on, from allow and deny lists in a policy file.
+
+The file is JSON, YAML or TOML and may deny recipients, resource IDs and source chains, or allow only the listed
+resource IDs:
+
+	{
+	    "deniedRecipients": ["0xff00..."],
+	    "deniedResources": ["0x00...01"],
+	    "allowedResources": ["0x00...02"],
+	    "deniedChains": [3]
+	}
+
+Recipients and resource IDs are hex encoded. A resource that is both allowed and denied is denied. The file can be
+reloaded while the relayer runs, invalid files are rejected and the previous rules are kept.
+*/
+package policy
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+)
+
+// Reasons a message is rejected, used as the reason label of the Rejections metric
+const (
+	DeniedRecipient    = "recipient"
+	DeniedResource     = "resource"
+	DeniedSource       = "source"
+	ResourceNotAllowed = "not_allowed"
+)
+
+// Rules are the contents of a policy file
+type Rules struct {
+	DeniedRecipients []string      `json:"deniedRecipients" yaml:"deniedRecipients" toml:"deniedRecipients"`
+	DeniedResources  []string      `json:"deniedResources" yaml:"deniedResources" toml:"deniedResources"`
+	AllowedResources []string      `json:"allowedResources" yaml:"allowedResources" toml:"allowedResources"`
+	DeniedChains     []msg.ChainId `json:"deniedChains" yaml:"deniedChains" toml:"deniedChains"`
+}
+
+// ruleSet holds the rules of a policy file with hex values normalized to lower case without 0x prefix
+type ruleSet struct {
+	deniedRecipients map[string]bool
+	deniedResources  map[string]bool
+	allowedResources map[string]bool // Every resource is allowed if empty
+	deniedChains     map[msg.ChainId]bool
+}
+
+func newRuleSet(r Rules) (*ruleSet, error) {
+	rs := &ruleSet{deniedChains: make(map[msg.ChainId]bool)}
+	var err error
+	rs.deniedRecipients, err = hexSet("deniedRecipients", r.DeniedRecipients)
+	if err != nil {
+		return nil, err
+	}
+	rs.deniedResources, err = hexSet("deniedResources", r.DeniedResources)
+	if err != nil {
+		return nil, err
+	}
+	rs.allowedResources, err = hexSet("allowedResources", r.AllowedResources)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range r.DeniedChains {
+		rs.deniedChains[id] = true
+	}
+	return rs, nil
+}
+
+func hexSet(field string, values []string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	for _, v := range values {
+		s := strings.ToLower(strings.TrimPrefix(v, "0x"))
+		if _, err := hex.DecodeString(s); err != nil || s == "" {
+			return nil, fmt.Errorf("invalid hex value %q in %s", v, field)
+		}
+		res[s] = true
+	}
+	return res, nil
+}
+
+// check returns the reason m is rejected and a description, or an empty reason if it is allowed
+func (rs *ruleSet) check(m msg.Message) (string, string) {
+	if rs.deniedChains[m.Source] {
+		return DeniedSource, fmt.Sprintf("source chain %d is denied", m.Source)
+	}
+	rId := m.ResourceId.Hex()
+	if rs.deniedResources[rId] {
+		return DeniedResource, fmt.Sprintf("resource %s is denied", rId)
+	}
+	if len(rs.allowedResources) != 0 && !rs.allowedResources[rId] {
+		return ResourceNotAllowed, fmt.Sprintf("resource %s is not allowed", rId)
+	}
+	if recipient, ok := recipientOf(m); ok && rs.deniedRecipients[recipient] {
+		return DeniedRecipient, fmt.Sprintf("recipient 0x%s is denied", recipient)
+	}
+	return "", ""
+}
+
+// recipientOf returns the hex encoded recipient of fungible and non-fungible transfers
+func recipientOf(m msg.Message) (string, bool) {
+	if m.Type != msg.FungibleTransfer && m.Type != msg.NonFungibleTransfer || len(m.Payload) < 2 {
+		return "", false
+	}
+	bz, ok := m.Payload[1].([]byte)
+	if !ok {
+		return "", false
+	}
+	return hex.EncodeToString(bz), true
+}
+
+// Policy is the policy loaded from a file. It is shared by all writers and is safe for concurrent use. All methods
+// are no-ops on nil, allowing every message.
+type Policy struct {
+	path       string
+	log        log15.Logger
+	lock       sync.RWMutex
+	rules      *ruleSet
+	Rejections *prometheus.CounterVec
+}
+
+// Load loads the policy file at path
+func Load(path string, log log15.Logger) (*Policy, error) {
+	rules, err := readRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{
+		path:  path,
+		log:   log,
+		rules: rules,
+		Rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_policy_rejections",
+			Help: "Number of messages the writers refused to vote on due to the policy",
+		}, []string{"src", "dst", "reason"}),
+	}, nil
+}
+
+func readRules(path string) (*ruleSet, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r Rules
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&r)
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(f).Decode(&r)
+	case ".toml":
+		_, err = toml.NewDecoder(f).Decode(&r)
+	default:
+		return nil, fmt.Errorf("unrecognized extention: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse policy %s: %w", path, err)
+	}
+	return newRuleSet(r)
+}
+
+// RegisterMetrics registers the Rejections metric
+func (p *Policy) RegisterMetrics() {
+	if p == nil {
+		return
+	}
+	prometheus.MustRegister(p.Rejections)
+}
+
+// Path returns the path of the policy file
+func (p *Policy) Path() string {
+	if p == nil {
+		return ""
+	}
+	return p.path
+}
+
+// Reload reads the policy file again. If it is invalid the current rules are kept.
+func (p *Policy) Reload() error {
+	if p == nil {
+		return nil
+	}
+	rules, err := readRules(p.path)
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	p.rules = rules
+	p.lock.Unlock()
+	p.log.Info("Reloaded policy", "path", p.path)
+	return nil
+}
+
+// Allows checks m against the policy. Rejected messages are logged to log and counted.
+func (p *Policy) Allows(m msg.Message, log log15.Logger) bool {
+	if p == nil {
+		return true
+	}
+	p.lock.RLock()
+	reason, desc := p.rules.check(m)
+	p.lock.RUnlock()
+	if reason == "" {
+		return true
+	}
+
+	log.Warn("Rejected message by policy", "reason", desc, "src", m.Source, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+	p.Rejections.WithLabelValues(strconv.Itoa(int(m.Source)), strconv.Itoa(int(m.Destination)), reason).Inc()
+	return false
+}
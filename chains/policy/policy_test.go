@@ -0,0 +1,128 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package policy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	allowedResource = msg.ResourceIdFromSlice([]byte{1})
+	deniedResource  = msg.ResourceIdFromSlice([]byte{2})
+	otherResource   = msg.ResourceIdFromSlice([]byte{3})
+)
+
+func writePolicy(t *testing.T, path, contents string) {
+	err := ioutil.WriteFile(path, []byte(contents), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "policy.json")
+	writePolicy(t, path, `{
+		"deniedRecipients": ["0xABCD"],
+		"deniedResources": ["0x`+deniedResource.Hex()+`"],
+		"allowedResources": ["`+allowedResource.Hex()+`", "0x`+deniedResource.Hex()+`"],
+		"deniedChains": [3]
+	}`)
+	p, err := Load(path, log15.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		m       msg.Message
+		allowed bool
+		reason  string
+	}{
+		{msg.NewFungibleTransfer(1, 2, 1, big.NewInt(1), allowedResource, []byte{0x12}), true, ""},
+		{msg.NewFungibleTransfer(1, 2, 2, big.NewInt(1), allowedResource, []byte{0xab, 0xcd}), false, DeniedRecipient},
+		{msg.NewNonFungibleTransfer(1, 2, 3, allowedResource, big.NewInt(1), []byte{0xab, 0xcd}, nil), false, DeniedRecipient},
+		{msg.NewFungibleTransfer(1, 2, 4, big.NewInt(1), deniedResource, []byte{0x12}), false, DeniedResource},
+		{msg.NewGenericTransfer(1, 2, 5, otherResource, []byte{}), false, ResourceNotAllowed},
+		{msg.NewFungibleTransfer(3, 2, 6, big.NewInt(1), allowedResource, []byte{0x12}), false, DeniedSource},
+	}
+	for _, tc := range testCases {
+		if p.Allows(tc.m, log15.New()) != tc.allowed {
+			t.Errorf("nonce %d: expected allowed to be %t", tc.m.DepositNonce, tc.allowed)
+		}
+		if tc.reason == "" {
+			continue
+		}
+		if v := testutil.ToFloat64(p.Rejections.WithLabelValues(strconv.Itoa(int(tc.m.Source)), "2", tc.reason)); v < 1 {
+			t.Errorf("nonce %d: expected rejection to be counted with reason %s", tc.m.DepositNonce, tc.reason)
+		}
+	}
+
+	// Invalid files are rejected and the current rules are kept
+	writePolicy(t, path, `{"deniedRecipients": ["not hex"]}`)
+	if err = p.Reload(); err == nil {
+		t.Fatal("expected invalid policy to be rejected")
+	}
+	if p.Allows(testCases[1].m, log15.New()) {
+		t.Fatal("expected previous rules to be kept")
+	}
+
+	writePolicy(t, path, `{"deniedChains": [1]}`)
+	if err = p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Allows(testCases[5].m, log15.New()) || p.Allows(testCases[0].m, log15.New()) {
+		t.Fatal("expected reloaded rules to apply")
+	}
+
+	// A nil policy allows every message
+	var none *Policy
+	if !none.Allows(testCases[1].m, log15.New()) {
+		t.Fatal("expected nil policy to allow messages")
+	}
+}
+
+func TestLoadYAMLAndTOML(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"policy.yaml": "deniedChains: [3]\ndeniedRecipients: [\"0xabcd\"]\n",
+		"policy.toml": "deniedChains = [3]\ndeniedRecipients = [\"0xabcd\"]\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		writePolicy(t, path, contents)
+		p, err := Load(path, log15.New())
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if p.Allows(msg.NewFungibleTransfer(3, 2, 1, big.NewInt(1), allowedResource, []byte{0x12}), log15.New()) {
+			t.Errorf("%s: expected source chain 3 to be denied", name)
+		}
+		if p.Allows(msg.NewFungibleTransfer(1, 2, 1, big.NewInt(1), allowedResource, []byte{0xab, 0xcd}), log15.New()) {
+			t.Errorf("%s: expected recipient to be denied", name)
+		}
+	}
+
+	if _, err = Load(filepath.Join(dir, "policy.txt"), log15.New()); err == nil {
+		t.Fatal("expected error for unknown extension")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+// ReorgedDeposits records the routed deposits that are no longer on the canonical chain of their source. A single
+// instance is shared by all chains, so writers can refuse to vote on deposits a listener flagged after routing them.
+type ReorgedDeposits struct {
+	deposits map[string]msg.Message
+	lock     sync.RWMutex
+}
+
+func NewReorgedDeposits() *ReorgedDeposits {
+	return &ReorgedDeposits{deposits: make(map[string]msg.Message)}
+}
+
+// Add records that the deposit of m was routed but is no longer on the canonical chain
+func (r *ReorgedDeposits) Add(m msg.Message) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deposits[CorrelationID(m.Source, m.Destination, m.DepositNonce)] = m
+}
+
+// Contains returns true if m is a message whose deposit is no longer on the canonical chain. A deposit with the same
+// nonce that differs from the flagged one, ie. the deposit found again after the reorganization, is not contained.
+func (r *ReorgedDeposits) Contains(m msg.Message) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	flagged, ok := r.deposits[CorrelationID(m.Source, m.Destination, m.DepositNonce)]
+	return ok && reflect.DeepEqual(flagged, m)
+}
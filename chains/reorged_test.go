@@ -0,0 +1,36 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+func TestReorgedDeposits(t *testing.T) {
+	r := NewReorgedDeposits()
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab})
+	if r.Contains(m) {
+		t.Fatal("unexpected reorged deposit")
+	}
+
+	r.Add(m)
+	if !r.Contains(m) {
+		t.Fatal("expected reorged deposit")
+	}
+
+	// The deposit found again with the same nonce after the reorganization may be voted on
+	replaced := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(200), verifyResource, []byte{0xab})
+	if r.Contains(replaced) {
+		t.Fatal("unexpected replaced deposit in reorged deposits")
+	}
+
+	var unset *ReorgedDeposits
+	unset.Add(m)
+	if unset.Contains(m) {
+		t.Fatal("unexpected reorged deposit in unset reorged deposits")
+	}
+}
@@ -24,6 +24,9 @@ As the writer receives messages from the router, it constructs proposals. If a p
 package substrate
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/blockstore"
 	"github.com/UltronFoundationDev/chainbridge-utils/core"
@@ -31,16 +34,27 @@ import (
 	"github.com/UltronFoundationDev/chainbridge-utils/keystore"
 	metrics "github.com/UltronFoundationDev/chainbridge-utils/metrics/types"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
 )
 
 var _ core.Chain = &Chain{}
 
 type Chain struct {
-	cfg      *core.ChainConfig // The config of the chain
-	conn     *Connection       // THe chains connection
-	listener *listener         // The listener of this chain
-	writer   *writer           // The writer of the chain
-	stop     chan<- int
+	cfg             *core.ChainConfig // The config of the chain
+	conn            *Connection       // THe chains connection
+	listener        *listener         // The listener of this chain
+	writer          *writer           // The writer of the chain
+	balance         *chains.BalanceMonitor
+	gate            *limits.Gate // Checks transfers against the limits before routing, nil if there are none
+	verify          bool         // Re-check deposits on the source chain before voting
+	stop            chan<- int   // Stops the listener
+	stopListener    sync.Once
+	connStop        chan<- int    // Stops the connection and balance monitor once the writer is drained
+	shutdownTimeout time.Duration // Time the writer is given to finish pending votes when stopping
 }
 
 // checkBlockstore queries the blockstore for the latest known block. If the latest block is
@@ -71,7 +85,21 @@ func InitializeChain(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- e
 	if err != nil {
 		return nil, err
 	}
-	startBlock := parseStartBlock(cfg)
+	queue, err := msgqueue.NewQueue(cfg.BlockstorePath, cfg.Id, kp.Address())
+	if err != nil {
+		return nil, err
+	}
+	// The pallet executes proposals itself, so nothing is left to do once a message is voted on
+	queue.SetVotedFinished()
+	deposits, err := loadDepositIndex(cfg.BlockstorePath, cfg.Id, kp.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	startBlock, err := parseStartBlock(cfg)
+	if err != nil {
+		return nil, err
+	}
 	if !cfg.FreshStart {
 		startBlock, err = checkBlockstore(bs, startBlock)
 		if err != nil {
@@ -80,8 +108,9 @@ func InitializeChain(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- e
 	}
 
 	stop := make(chan int)
+	connStop := make(chan int)
 	// Setup connection
-	conn := NewConnection(cfg.Endpoint, cfg.Name, krp, logger, stop, sysErr)
+	conn := NewConnection(cfg.Endpoint, cfg.Name, krp, logger, connStop, sysErr)
 	err = conn.Connect()
 	if err != nil {
 		return nil, err
@@ -100,17 +129,52 @@ func InitializeChain(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- e
 		startBlock = uint64(curr.Number)
 	}
 
-	ue := parseUseExtended(cfg)
+	ue, err := parseUseExtended(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dustPolicy, err := parseDustPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	amounts := amount.NewConverter(cfg.Decimals, dustPolicy)
+
+	verify, err := parseVerifyDeposits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	confirmations, err := parseVerifyConfirmations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := parseBalanceThreshold(cfg)
+	if err != nil {
+		return nil, err
+	}
+	balance := chains.NewBalanceMonitor(conn.freeBalance, threshold, logger, connStop)
+	if m != nil {
+		balance.RegisterMetrics(cfg.Name)
+	}
 
 	// Setup listener & writer
 	l := NewListener(conn, cfg.Name, cfg.Id, startBlock, logger, bs, stop, sysErr, m)
 	w := NewWriter(conn, logger, sysErr, m, ue)
+	w.setQueue(queue)
+	l.setAmounts(amounts)
+	l.setDepositIndex(deposits)
+	l.setVerifyConfirmations(confirmations)
+	w.setAmounts(amounts)
 	return &Chain{
 		cfg:      cfg,
 		conn:     conn,
 		listener: l,
 		writer:   w,
+		balance:  balance,
+		verify:   verify,
 		stop:     stop,
+		connStop: connStop,
 	}, nil
 }
 
@@ -119,19 +183,100 @@ func (c *Chain) Start() error {
 	if err != nil {
 		return err
 	}
+
+	err = c.writer.start()
+	if err != nil {
+		return err
+	}
+	c.balance.Start()
+
 	c.conn.log.Debug("Successfully started chain", "chainId", c.cfg.Id)
 	return nil
 }
 
 func (c *Chain) SetRouter(r *core.Router) {
 	r.Listen(c.cfg.Id, c.writer)
-	c.listener.setRouter(r)
+	var router chains.Router = chains.NewLoggingRouter(r, c.listener.log)
+	if c.gate != nil {
+		c.gate.SetRouter(router)
+		router = c.gate
+	}
+	c.listener.setRouter(router)
+}
+
+// SetTransferGate sets the gate the listener's transfers pass before being routed. It must be called before the
+// chain is added to the core.
+func (c *Chain) SetTransferGate(g *limits.Gate) {
+	c.gate = g
+}
+
+// TransferGate returns the gate the listener's transfers pass, or nil if the chain has no limits
+func (c *Chain) TransferGate() *limits.Gate {
+	return c.gate
 }
 
 func (c *Chain) LatestBlock() metrics.LatestBlock {
 	return c.listener.latestBlock
 }
 
+// SetLifecycleMetrics sets the message lifecycle metrics shared by all chains
+func (c *Chain) SetLifecycleMetrics(m *chains.LifecycleMetrics) {
+	c.listener.setLifecycleMetrics(m)
+	c.writer.setLifecycleMetrics(m)
+}
+
+// SetPolicy sets the policy the writer checks messages against before voting
+func (c *Chain) SetPolicy(p *policy.Policy) {
+	c.writer.setPolicy(p)
+}
+
+// DepositVerifier returns the verifier of deposits made on this chain
+func (c *Chain) DepositVerifier() chains.DepositVerifier {
+	return c.listener
+}
+
+// SetVerifier sets the verifier the writer re-checks deposits with before voting, if verifyDeposits is enabled
+func (c *Chain) SetVerifier(v *chains.Verifier) {
+	if c.verify {
+		c.writer.setVerifier(v)
+	}
+}
+
+// SetReorgedDeposits sets the set of deposits no longer on the canonical chain, which the writer checks before voting
+func (c *Chain) SetReorgedDeposits(r *chains.ReorgedDeposits) {
+	c.writer.setReorgedDeposits(r)
+}
+
+// BalanceMonitor returns the monitor of the relayer balance
+func (c *Chain) BalanceMonitor() *chains.BalanceMonitor {
+	return c.balance
+}
+
+// ListenerControl returns the control used to pause or re-scan the listener
+func (c *Chain) ListenerControl() *chains.ListenerControl {
+	return &c.listener.control
+}
+
+// MessageQueue returns the queue of messages routed to the writer
+func (c *Chain) MessageQueue() *msgqueue.Queue {
+	return c.writer.queue
+}
+
+// DisableMessageQueue stops the writer from recording messages in the queue, for when another process owns it
+func (c *Chain) DisableMessageQueue() {
+	c.writer.setQueue(nil)
+}
+
+// Resubmit resolves a message in the writer's queue again
+func (c *Chain) Resubmit(m msg.Message) {
+	c.writer.resubmit(m)
+}
+
+// ResolveMessage resolves a message with the chain's writer, returning once the writer is done with it
+func (c *Chain) ResolveMessage(m msg.Message) bool {
+	return c.writer.ResolveMessage(m)
+}
+
 func (c *Chain) Id() msg.ChainId {
 	return c.cfg.Id
 }
@@ -140,6 +285,22 @@ func (c *Chain) Name() string {
 	return c.cfg.Name
 }
 
+// SetShutdownTimeout sets how long Stop waits for pending votes to complete
+func (c *Chain) SetShutdownTimeout(d time.Duration) {
+	c.shutdownTimeout = d
+}
+
+// StopListener stops the listener so no new deposits are routed. The writer keeps running until Stop.
+func (c *Chain) StopListener() {
+	c.stopListener.Do(func() {
+		close(c.stop)
+	})
+}
+
+// Stop signals to any running routines to exit. The listener is stopped first so no new deposits are routed, then
+// the writer is given up to the shutdown timeout to finish pending votes.
 func (c *Chain) Stop() {
-	close(c.stop)
+	c.StopListener()
+	c.writer.drainer.Drain(c.shutdownTimeout)
+	close(c.connStop)
 }
@@ -4,29 +4,76 @@
 package substrate
 
 import (
+	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 )
 
-func parseStartBlock(cfg *core.ChainConfig) uint64 {
+func parseStartBlock(cfg *core.ChainConfig) (uint64, error) {
 	if blk, ok := cfg.Opts["startBlock"]; ok {
 		res, err := strconv.ParseUint(blk, 10, 32)
 		if err != nil {
-			panic(err)
+			return 0, fmt.Errorf("unable to parse startBlock: %w", err)
 		}
-		return res
+		return res, nil
 	}
-	return 0
+	return 0, nil
 }
 
-func parseUseExtended(cfg *core.ChainConfig) bool {
+func parseUseExtended(cfg *core.ChainConfig) (bool, error) {
 	if b, ok := cfg.Opts["useExtendedCall"]; ok {
 		res, err := strconv.ParseBool(b)
 		if err != nil {
-			panic(err)
+			return false, fmt.Errorf("unable to parse useExtendedCall: %w", err)
 		}
-		return res
+		return res, nil
 	}
-	return false
+	return false, nil
+}
+
+func parseBalanceThreshold(cfg *core.ChainConfig) (*big.Int, error) {
+	if threshold, ok := cfg.Opts["balanceThreshold"]; ok {
+		res, ok := new(big.Int).SetString(threshold, 10)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse balanceThreshold: %q", threshold)
+		}
+		return res, nil
+	}
+	return nil, nil
+}
+
+func parseDustPolicy(cfg *core.ChainConfig) (amount.DustPolicy, error) {
+	if policy, ok := cfg.Opts["dustPolicy"]; ok {
+		res, err := amount.ParseDustPolicy(policy)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse dustPolicy: %w", err)
+		}
+		return res, nil
+	}
+	return amount.DefaultDustPolicy, nil
+}
+
+func parseVerifyDeposits(cfg *core.ChainConfig) (bool, error) {
+	if b, ok := cfg.Opts["verifyDeposits"]; ok {
+		res, err := strconv.ParseBool(b)
+		if err != nil {
+			return false, fmt.Errorf("unable to parse verifyDeposits: %w", err)
+		}
+		return res, nil
+	}
+	return false, nil
+}
+
+func parseVerifyConfirmations(cfg *core.ChainConfig) (uint64, error) {
+	if n, ok := cfg.Opts["verifyConfirmations"]; ok {
+		res, err := strconv.ParseUint(n, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse verifyConfirmations: %w", err)
+		}
+		return res, nil
+	}
+	return 0, nil
 }
@@ -13,7 +13,10 @@ func TestParseStartBlock(t *testing.T) {
 	// Valid option included in config
 	cfg := &core.ChainConfig{Opts: map[string]string{"startBlock": "1000"}}
 
-	blk := parseStartBlock(cfg)
+	blk, err := parseStartBlock(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if blk != 1000 {
 		t.Fatalf("Got: %d Expected: %d", blk, 1000)
@@ -22,9 +25,20 @@ func TestParseStartBlock(t *testing.T) {
 	// Not included in config
 	cfg = &core.ChainConfig{Opts: map[string]string{}}
 
-	blk = parseStartBlock(cfg)
+	blk, err = parseStartBlock(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if blk != 0 {
 		t.Fatalf("Got: %d Expected: %d", blk, 0)
 	}
+
+	// Invalid option
+	cfg = &core.ChainConfig{Opts: map[string]string{"startBlock": "latest"}}
+
+	_, err = parseStartBlock(cfg)
+	if err == nil {
+		t.Fatal("expected invalid startBlock to fail")
+	}
 }
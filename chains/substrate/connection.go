@@ -5,7 +5,9 @@ package substrate
 
 import (
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
@@ -83,7 +85,12 @@ func (c *Connection) Connect() error {
 // SubmitTx constructs and submits an extrinsic to call the method with the given arguments.
 // All args are passed directly into GSRPC. GSRPC types are recommended to avoid serialization inconsistencies.
 func (c *Connection) SubmitTx(method utils.Method, args ...interface{}) error {
-	c.log.Debug("Submitting substrate call...", "method", method, "sender", c.key.Address)
+	return c.submitTx(c.log, method, args...)
+}
+
+// submitTx submits an extrinsic like SubmitTx, logging to log, eg. to include the correlation ID of a message
+func (c *Connection) submitTx(log log15.Logger, method utils.Method, args ...interface{}) error {
+	log.Debug("Submitting substrate call...", "method", method, "sender", c.key.Address)
 
 	meta := c.getMetadata()
 
@@ -138,13 +145,13 @@ func (c *Connection) SubmitTx(method utils.Method, args ...interface{}) error {
 	if err != nil {
 		return fmt.Errorf("submission of extrinsic failed: %w", err)
 	}
-	c.log.Trace("Extrinsic submission succeeded")
+	log.Trace("Extrinsic submission succeeded")
 	defer sub.Unsubscribe()
 
-	return c.watchSubmission(sub)
+	return c.watchSubmission(log, sub)
 }
 
-func (c *Connection) watchSubmission(sub *author.ExtrinsicStatusSubscription) error {
+func (c *Connection) watchSubmission(log log15.Logger, sub *author.ExtrinsicStatusSubscription) error {
 	for {
 		select {
 		case <-c.stop:
@@ -152,7 +159,7 @@ func (c *Connection) watchSubmission(sub *author.ExtrinsicStatusSubscription) er
 		case status := <-sub.Chan():
 			switch {
 			case status.IsInBlock:
-				c.log.Trace("Extrinsic included in block", "block", status.AsInBlock.Hex())
+				log.Trace("Extrinsic included in block", "block", status.AsInBlock.Hex())
 				return nil
 			case status.IsRetracted:
 				return fmt.Errorf("extrinsic retracted: %s", status.AsRetracted.Hex())
@@ -162,7 +169,7 @@ func (c *Connection) watchSubmission(sub *author.ExtrinsicStatusSubscription) er
 				return fmt.Errorf("extrinsic invalid")
 			}
 		case err := <-sub.Err():
-			c.log.Trace("Extrinsic subscription error", "err", err)
+			log.Trace("Extrinsic subscription error", "err", err)
 			return err
 		}
 	}
@@ -224,6 +231,35 @@ func (c *Connection) getLatestNonce() (types.U32, error) {
 
 	return acct.Nonce, nil
 }
+
+// freeBalance returns the free balance of the relayer account
+func (c *Connection) freeBalance() (*big.Int, error) {
+	var acct types.AccountInfo
+	exists, err := c.queryStorage("System", "Account", c.key.PublicKey, nil, &acct)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return big.NewInt(0), nil
+	}
+	return acct.Data.Free.Int, nil
+}
+
+// blockTime returns the time block hash was produced
+func (c *Connection) blockTime(hash types.Hash) (time.Time, error) {
+	meta := c.getMetadata()
+	key, err := types.CreateStorageKey(&meta, "Timestamp", "Now", nil, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var now types.U64
+	_, err = c.api.RPC.State.GetStorage(key, &now, hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(now)*int64(time.Millisecond)), nil
+}
+
 func (c *Connection) Close() {
 	// TODO: Anything required to shutdown GRPC?
 }
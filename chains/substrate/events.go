@@ -10,10 +10,11 @@ import (
 	events "github.com/ChainSafe/chainbridge-substrate-events"
 	"github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
 )
 
 type eventName string
-type eventHandler func(interface{}, log15.Logger) (msg.Message, error)
+type eventHandler func(interface{}, msg.ChainId, log15.Logger) (msg.Message, error)
 
 const FungibleTransfer eventName = "FungibleTransfer"
 const NonFungibleTransfer eventName = "NonFungibleTransfer"
@@ -28,17 +29,18 @@ var Subscriptions = []struct {
 	{GenericTransfer, genericTransferHandler},
 }
 
-func fungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, error) {
+func fungibleTransferHandler(evtI interface{}, src msg.ChainId, log log15.Logger) (msg.Message, error) {
 	evt, ok := evtI.(events.EventFungibleTransfer)
 	if !ok {
 		return msg.Message{}, fmt.Errorf("failed to cast EventFungibleTransfer type")
 	}
 
 	resourceId := msg.ResourceId(evt.ResourceId)
+	log = log.New(chains.CorrelationKey, chains.CorrelationID(src, msg.ChainId(evt.Destination), msg.Nonce(evt.DepositNonce)))
 	log.Info("Got fungible transfer event!", "destination", evt.Destination, "resourceId", resourceId.Hex(), "amount", evt.Amount)
 
 	return msg.NewFungibleTransfer(
-		0, // Unset
+		src,
 		msg.ChainId(evt.Destination),
 		msg.Nonce(evt.DepositNonce),
 		evt.Amount.Int,
@@ -47,16 +49,17 @@ func fungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, e
 	), nil
 }
 
-func nonFungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, error) {
+func nonFungibleTransferHandler(evtI interface{}, src msg.ChainId, log log15.Logger) (msg.Message, error) {
 	evt, ok := evtI.(events.EventNonFungibleTransfer)
 	if !ok {
 		return msg.Message{}, fmt.Errorf("failed to cast EventNonFungibleTransfer type")
 	}
 
+	log = log.New(chains.CorrelationKey, chains.CorrelationID(src, msg.ChainId(evt.Destination), msg.Nonce(evt.DepositNonce)))
 	log.Info("Got non-fungible transfer event!", "destination", evt.Destination, "resourceId", evt.ResourceId)
 
 	return msg.NewNonFungibleTransfer(
-		0, // Unset
+		src,
 		msg.ChainId(evt.Destination),
 		msg.Nonce(evt.DepositNonce),
 		msg.ResourceId(evt.ResourceId),
@@ -66,16 +69,17 @@ func nonFungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message
 	), nil
 }
 
-func genericTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, error) {
+func genericTransferHandler(evtI interface{}, src msg.ChainId, log log15.Logger) (msg.Message, error) {
 	evt, ok := evtI.(events.EventGenericTransfer)
 	if !ok {
 		return msg.Message{}, fmt.Errorf("failed to cast EventGenericTransfer type")
 	}
 
+	log = log.New(chains.CorrelationKey, chains.CorrelationID(src, msg.ChainId(evt.Destination), msg.Nonce(evt.DepositNonce)))
 	log.Info("Got generic transfer event!", "destination", evt.Destination, "resourceId", evt.ResourceId)
 
 	return msg.NewGenericTransfer(
-		0, // Unset
+		src,
 		msg.ChainId(evt.Destination),
 		msg.Nonce(evt.DepositNonce),
 		msg.ResourceId(evt.ResourceId),
@@ -14,6 +14,7 @@ import (
 	metrics "github.com/UltronFoundationDev/chainbridge-utils/metrics/types"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
 	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/substrate"
 	"github.com/centrifuge/go-substrate-rpc-client/types"
 )
@@ -31,6 +32,13 @@ type listener struct {
 	sysErr        chan<- error
 	latestBlock   metrics.LatestBlock
 	metrics       *metrics.ChainMetrics
+	lifecycle     *chains.LifecycleMetrics
+	eventsTime    time.Time              // Time of the block whose events are being handled
+	eventsBlock   uint64                 // Number of the block whose events are being handled
+	deposits      *depositIndex          // Blocks of recently routed deposits, used to verify them
+	confirmations uint64                 // Confirmations after finalization deposits need to be verified
+	amounts       *amount.Converter      // Converts fungible amounts to the decimals of the destination
+	control       chains.ListenerControl // Pause and re-scan requests from the admin API
 }
 
 // Frequency of polling for a new block
@@ -50,6 +58,8 @@ func NewListener(conn *Connection, name string, id msg.ChainId, startBlock uint6
 		sysErr:        sysErr,
 		latestBlock:   metrics.LatestBlock{LastUpdated: time.Now()},
 		metrics:       m,
+		amounts:       amount.NewConverter(nil, amount.DefaultDustPolicy),
+		deposits:      newDepositIndex(),
 	}
 }
 
@@ -57,6 +67,26 @@ func (l *listener) setRouter(r chains.Router) {
 	l.router = r
 }
 
+// setAmounts sets the converter of fungible amounts
+func (l *listener) setAmounts(c *amount.Converter) {
+	l.amounts = c
+}
+
+// setDepositIndex sets the index the blocks of routed deposits are recorded in
+func (l *listener) setDepositIndex(d *depositIndex) {
+	l.deposits = d
+}
+
+// setVerifyConfirmations sets the number of blocks after finalization a deposit needs to be verified
+func (l *listener) setVerifyConfirmations(n uint64) {
+	l.confirmations = n
+}
+
+// setLifecycleMetrics sets the message lifecycle metrics, which are optional
+func (l *listener) setLifecycleMetrics(m *chains.LifecycleMetrics) {
+	l.lifecycle = m
+}
+
 // start creates the initial subscription for all events
 func (l *listener) start() error {
 	// Check whether latest is less than starting block
@@ -107,6 +137,17 @@ func (l *listener) pollBlocks() error {
 		case <-l.stop:
 			return errors.New("terminated")
 		default:
+			if block := l.control.TakeRescan(); block != nil {
+				l.log.Info("Re-scanning from block", "block", block, "current", currentBlock)
+				currentBlock = block.Uint64()
+				retry = BlockRetryLimit
+			}
+			l.control.SetPosition(new(big.Int).SetUint64(currentBlock))
+			if l.control.Paused() {
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+
 			// No more retries, goto next block
 			if retry == 0 {
 				l.sysErr <- fmt.Errorf("event polling retries exceeded (chain=%d, name=%s)", l.chainId, l.name)
@@ -154,7 +195,7 @@ func (l *listener) pollBlocks() error {
 				continue
 			}
 
-			err = l.processEvents(hash)
+			err = l.processEvents(currentBlock, hash)
 			if err != nil {
 				l.log.Error("Failed to process events in block", "block", currentBlock, "err", err)
 				retry--
@@ -181,30 +222,48 @@ func (l *listener) pollBlocks() error {
 }
 
 // processEvents fetches a block and parses out the events, calling Listener.handleEvents()
-func (l *listener) processEvents(hash types.Hash) error {
+func (l *listener) processEvents(block uint64, hash types.Hash) error {
 	l.log.Trace("Fetching block for events", "hash", hash.Hex())
+	e, err := l.blockEvents(hash)
+	if err != nil {
+		return err
+	}
+	l.eventsBlock = block
+
+	if l.lifecycle != nil {
+		l.eventsTime, err = l.conn.blockTime(hash)
+		if err != nil {
+			l.log.Warn("Failed to fetch block time", "hash", hash.Hex(), "err", err)
+			l.eventsTime = time.Now()
+		}
+	}
+
+	l.handleEvents(e)
+	l.log.Trace("Finished processing events", "block", hash.Hex())
+
+	return nil
+}
+
+// blockEvents fetches and decodes the events of the block with the given hash
+func (l *listener) blockEvents(hash types.Hash) (utils.Events, error) {
 	meta := l.conn.getMetadata()
 	key, err := types.CreateStorageKey(&meta, "System", "Events", nil, nil)
 	if err != nil {
-		return err
+		return utils.Events{}, err
 	}
 
 	var records types.EventRecordsRaw
 	_, err = l.conn.api.RPC.State.GetStorage(key, &records, hash)
 	if err != nil {
-		return err
+		return utils.Events{}, err
 	}
 
 	e := utils.Events{}
 	err = records.DecodeEventRecords(&meta, &e)
 	if err != nil {
-		return err
+		return utils.Events{}, err
 	}
-
-	l.handleEvents(e)
-	l.log.Trace("Finished processing events", "block", hash.Hex())
-
-	return nil
+	return e, nil
 }
 
 // handleEvents calls the associated handler for all registered event types
@@ -212,19 +271,19 @@ func (l *listener) handleEvents(evts utils.Events) {
 	if l.subscriptions[FungibleTransfer] != nil {
 		for _, evt := range evts.ChainBridge_FungibleTransfer {
 			l.log.Trace("Handling FungibleTransfer event")
-			l.submitMessage(l.subscriptions[FungibleTransfer](evt, l.log))
+			l.submitMessage(l.subscriptions[FungibleTransfer](evt, l.chainId, l.log))
 		}
 	}
 	if l.subscriptions[NonFungibleTransfer] != nil {
 		for _, evt := range evts.ChainBridge_NonFungibleTransfer {
 			l.log.Trace("Handling NonFungibleTransfer event")
-			l.submitMessage(l.subscriptions[NonFungibleTransfer](evt, l.log))
+			l.submitMessage(l.subscriptions[NonFungibleTransfer](evt, l.chainId, l.log))
 		}
 	}
 	if l.subscriptions[GenericTransfer] != nil {
 		for _, evt := range evts.ChainBridge_GenericTransfer {
 			l.log.Trace("Handling GenericTransfer event")
-			l.submitMessage(l.subscriptions[GenericTransfer](evt, l.log))
+			l.submitMessage(l.subscriptions[GenericTransfer](evt, l.chainId, l.log))
 		}
 	}
 
@@ -237,15 +296,44 @@ func (l *listener) handleEvents(evts utils.Events) {
 	}
 }
 
-// submitMessage inserts the chainId into the msg and sends it to the router
+// submitMessage converts the amount of fungible transfers and sends the msg to the router
 func (l *listener) submitMessage(m msg.Message, err error) {
 	if err != nil {
-		log15.Error("Critical error processing event", "err", err)
+		l.log.Error("Critical error processing event", "err", err)
 		return
 	}
-	m.Source = l.chainId
+	if m.Type == msg.FungibleTransfer {
+		m, err = l.convertAmount(m)
+		if err != nil {
+			chains.MessageLogger(l.log, m).Error("Rejected deposit", "err", err)
+			return
+		}
+	}
+	l.lifecycle.DepositDetected(m, l.eventsTime)
+	err = l.deposits.add(m, l.eventsBlock)
+	if err != nil {
+		chains.MessageLogger(l.log, m).Error("Failed to record deposit block, verifying it needs a re-scan after a restart", "err", err)
+	}
 	err = l.router.Send(m)
 	if err != nil {
-		log15.Error("failed to process event", "err", err)
+		chains.MessageLogger(l.log, m).Error("failed to process event", "err", err)
 	}
 }
+
+// convertAmount converts the amount of a fungible transfer to the decimals of the destination
+func (l *listener) convertAmount(m msg.Message) (msg.Message, error) {
+	deposited := new(big.Int).SetBytes(m.Payload[0].([]byte))
+	conv, err := l.amounts.Convert(m.Destination, m.ResourceId, deposited)
+	if err != nil {
+		return m, err
+	}
+	log := chains.MessageLogger(l.log, m)
+	if conv.Refund {
+		log.Warn("Dust flagged for refund", "dust", conv.Dust, "amount", deposited, "rId", m.ResourceId.Hex())
+	} else if conv.Dust.Sign() != 0 {
+		log.Info("Dropped dust", "dust", conv.Dust, "amount", deposited, "rId", m.ResourceId.Hex())
+	}
+
+	m.Payload = append([]interface{}{conv.Amount.Bytes()}, m.Payload[1:]...)
+	return m, nil
+}
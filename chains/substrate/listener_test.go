@@ -12,6 +12,7 @@ import (
 
 	"github.com/UltronFoundationDev/chainbridge-utils/blockstore"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/substrate"
 	subtest "github.com/UltronFoundationDev/chainbridge/shared/substrate/testing"
 	"github.com/centrifuge/go-substrate-rpc-client/types"
@@ -91,6 +92,32 @@ func Test_FungibleTransferEvent(t *testing.T) {
 	verifyResultingMessage(t, context.router, context.lSysErr, expected)
 }
 
+func TestListener_ConvertAmount(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	decimals := map[msg.ChainId]map[string][2]uint8{ForeignChain: {rId.Hex(): {12, 18}}}
+	recipient := BobKey.PublicKey
+
+	l := &listener{log: AliceTestLogger, amounts: amount.NewConverter(decimals, amount.DustReject)}
+	m, err := l.convertAmount(msg.NewFungibleTransfer(ThisChain, ForeignChain, 1, big.NewInt(1500000000000), rId, recipient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := msg.NewFungibleTransfer(ThisChain, ForeignChain, 1, big.NewInt(0).Mul(big.NewInt(1500000000000), big.NewInt(1000000)), rId, recipient)
+	if err = compareMessage(expected, m); err != nil {
+		t.Fatal(err)
+	}
+
+	// Transfers to chains without decimals are unchanged
+	unchanged := msg.NewFungibleTransfer(ThisChain, ForeignChain+1, 1, big.NewInt(7), rId, recipient)
+	m, err = l.convertAmount(unchanged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = compareMessage(unchanged, m); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func Test_NonFungibleTransferEvent(t *testing.T) {
 	// First, mint a token to transfer
 	tokenId := big.NewInt(1212)
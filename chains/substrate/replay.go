@@ -0,0 +1,41 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/blockstore"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+)
+
+// Replay processes the events of block and routes the resulting messages with r. The relayer's key is not loaded
+// and nothing is written to the blockstore.
+func Replay(cfg *core.ChainConfig, logger log15.Logger, r chains.Router, block uint64) error {
+	stop := make(chan int)
+	defer close(stop)
+	conn := NewConnection(cfg.Endpoint, cfg.Name, nil, logger, stop, nil)
+	err := conn.Connect()
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", cfg.Endpoint, err)
+	}
+	defer conn.Close()
+
+	l := NewListener(conn, cfg.Name, cfg.Id, block, logger, &blockstore.EmptyStore{}, stop, nil, nil)
+	for _, sub := range Subscriptions {
+		err = l.registerEventHandler(sub.name, sub.handler)
+		if err != nil {
+			return err
+		}
+	}
+	l.setRouter(r)
+
+	hash, err := conn.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return fmt.Errorf("unable to get hash of block %d: %w", block, err)
+	}
+	return l.processEvents(block, hash)
+}
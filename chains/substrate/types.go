@@ -59,7 +59,10 @@ func (p *proposal) encode() ([]byte, error) {
 }
 
 func (w *writer) createFungibleProposal(m msg.Message) (*proposal, error) {
-	bigAmt := big.NewInt(0).SetBytes(m.Payload[0].([]byte))
+	bigAmt, err := w.incomingAmount(m)
+	if err != nil {
+		return nil, err
+	}
 	amount := types.NewU128(*bigAmt)
 	recipient := types.NewAccountID(m.Payload[1].([]byte))
 	depositNonce := types.U64(m.DepositNonce)
@@ -95,6 +98,22 @@ func (w *writer) createFungibleProposal(m msg.Message) (*proposal, error) {
 	}, nil
 }
 
+// incomingAmount returns the amount of a fungible transfer converted to the decimals of this chain
+func (w *writer) incomingAmount(m msg.Message) (*big.Int, error) {
+	transferred := new(big.Int).SetBytes(m.Payload[0].([]byte))
+	conv, err := w.amounts.ConvertIncoming(m.Source, m.ResourceId, transferred)
+	if err != nil {
+		return nil, err
+	}
+	log := w.messageLog(m)
+	if conv.Refund {
+		log.Warn("Dust flagged for refund", "dust", conv.Dust, "amount", transferred, "rId", m.ResourceId.Hex())
+	} else if conv.Dust.Sign() != 0 {
+		log.Info("Dropped dust", "dust", conv.Dust, "amount", transferred, "rId", m.ResourceId.Hex())
+	}
+	return conv.Amount, nil
+}
+
 func (w *writer) createNonFungibleProposal(m msg.Message) (*proposal, error) {
 	tokenId := types.NewU256(*big.NewInt(0).SetBytes(m.Payload[0].([]byte)))
 	recipient := types.NewAccountID(m.Payload[1].([]byte))
@@ -0,0 +1,54 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+)
+
+// ValidateConfig runs the same checks as InitializeChain without loading keys or starting the chain. The network
+// checks (connecting to the endpoint and comparing the chain ID) are skipped if offline is set.
+func ValidateConfig(cfg *core.ChainConfig, logger log15.Logger, offline bool) error {
+	_, err := parseStartBlock(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseUseExtended(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseBalanceThreshold(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseDustPolicy(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseVerifyDeposits(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseVerifyConfirmations(cfg)
+	if err != nil {
+		return err
+	}
+
+	if offline {
+		return nil
+	}
+
+	stop := make(chan int)
+	defer close(stop)
+	conn := NewConnection(cfg.Endpoint, cfg.Name, nil, logger, stop, nil)
+	err = conn.Connect()
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", cfg.Endpoint, err)
+	}
+
+	return conn.checkChainId(cfg.Id)
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	utils "github.com/UltronFoundationDev/chainbridge/shared/substrate"
+)
+
+// DepositRetention is how long the block of a routed deposit is remembered for verification
+var DepositRetention = time.Hour * 24
+
+var _ chains.DepositVerifier = &listener{}
+
+// indexedDeposit is the block of a routed deposit, also its on-disk representation
+type indexedDeposit struct {
+	Block uint64    `json:"block"`
+	Added time.Time `json:"added"`
+}
+
+// depositIndex records the blocks of the deposits routed by the listener. Substrate deposits only exist as events,
+// so the block is needed to read them again. The index is kept in a file if it has a path, so deposits routed before
+// a restart can still be verified.
+type depositIndex struct {
+	path     string // File the index is persisted to, empty to keep it in memory only
+	deposits map[string]indexedDeposit
+	lock     sync.Mutex
+}
+
+func newDepositIndex() *depositIndex {
+	return &depositIndex{deposits: make(map[string]indexedDeposit)}
+}
+
+// loadDepositIndex opens the deposit index of the given chain and relayer in path, creating it if it does not exist
+func loadDepositIndex(path string, chain msg.ChainId, relayer string) (*depositIndex, error) {
+	path, err := msgqueue.FilePath(path, chain, relayer, "deposits")
+	if err != nil {
+		return nil, err
+	}
+	d := newDepositIndex()
+	d.path = path
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &d.deposits)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse deposit index %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// add records the block of the deposit of m and forgets deposits older than DepositRetention
+func (d *depositIndex) add(m msg.Message, block uint64) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := time.Now()
+	for k, dep := range d.deposits {
+		if now.Sub(dep.Added) > DepositRetention {
+			delete(d.deposits, k)
+		}
+	}
+	d.deposits[chains.CorrelationID(m.Source, m.Destination, m.DepositNonce)] = indexedDeposit{Block: block, Added: now}
+
+	if d.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(d.deposits)
+	if err != nil {
+		return err
+	}
+	return msgqueue.WriteFile(d.path, data)
+}
+
+// block returns the block of the deposit of m, and false if it is unknown
+func (d *depositIndex) block(m msg.Message) (uint64, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	dep, ok := d.deposits[chains.CorrelationID(m.Source, m.Destination, m.DepositNonce)]
+	return dep.Block, ok
+}
+
+// VerifyDeposit reads the events of the block the deposit of m was found in, once the block has the required
+// confirmations after finalization
+func (l *listener) VerifyDeposit(m msg.Message) error {
+	block, ok := l.deposits.block(m)
+	if !ok {
+		return fmt.Errorf("block of deposit %d to chain %d is unknown, re-scan the source chain to verify it", m.DepositNonce, m.Destination)
+	}
+
+	finalizedHash, err := l.conn.api.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return err
+	}
+	finalized, err := l.conn.api.RPC.Chain.GetHeader(finalizedHash)
+	if err != nil {
+		return err
+	}
+	if uint64(finalized.Number) < block+l.confirmations {
+		return chains.ErrUnconfirmed
+	}
+
+	hash, err := l.conn.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return err
+	}
+	evts, err := l.blockEvents(hash)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := l.findDeposit(evts, m)
+	if err != nil {
+		return err
+	}
+	return chains.MatchDeposit(m, deposit)
+}
+
+// findDeposit constructs the message of the transfer event with the destination and deposit nonce of m
+func (l *listener) findDeposit(evts utils.Events, m msg.Message) (msg.Message, error) {
+	log := log15.New()
+	log.SetHandler(log15.DiscardHandler())
+
+	for _, evt := range evts.ChainBridge_FungibleTransfer {
+		if msg.ChainId(evt.Destination) == m.Destination && msg.Nonce(evt.DepositNonce) == m.DepositNonce {
+			deposit, err := fungibleTransferHandler(evt, l.chainId, log)
+			if err != nil {
+				return msg.Message{}, err
+			}
+			return l.convertAmount(deposit)
+		}
+	}
+	for _, evt := range evts.ChainBridge_NonFungibleTransfer {
+		if msg.ChainId(evt.Destination) == m.Destination && msg.Nonce(evt.DepositNonce) == m.DepositNonce {
+			return nonFungibleTransferHandler(evt, l.chainId, log)
+		}
+	}
+	for _, evt := range evts.ChainBridge_GenericTransfer {
+		if msg.ChainId(evt.Destination) == m.Destination && msg.Nonce(evt.DepositNonce) == m.DepositNonce {
+			return genericTransferHandler(evt, l.chainId, log)
+		}
+	}
+	return msg.Message{}, fmt.Errorf("deposit %d to chain %d not found in block", m.DepositNonce, m.Destination)
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/ChainSafe/log15"
 	metrics "github.com/UltronFoundationDev/chainbridge-utils/metrics/types"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
+	"github.com/UltronFoundationDev/chainbridge/chains/msgqueue"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/substrate"
 	"github.com/centrifuge/go-substrate-rpc-client/types"
 )
@@ -23,12 +27,25 @@ var _ core.Writer = &writer{}
 var AcknowledgeProposal utils.Method = utils.BridgePalletName + ".acknowledge_proposal"
 var TerminatedError = errors.New("terminated")
 
+// Reasons returned by proposalValid for skipping a proposal
+const (
+	ReasonAlreadyVoted     = "already voted"
+	ReasonProposalComplete = "proposal complete"
+)
+
 type writer struct {
 	conn       *Connection
 	log        log15.Logger
 	sysErr     chan<- error
 	metrics    *metrics.ChainMetrics
-	extendCall bool // Extend extrinsic calls to substrate with ResourceID.Used for backward compatibility with example pallet.
+	lifecycle  *chains.LifecycleMetrics
+	amounts    *amount.Converter
+	policy     *policy.Policy
+	verifier   *chains.Verifier        // Re-checks deposits on the source chain before voting, may be nil
+	reorged    *chains.ReorgedDeposits // Deposits flagged as no longer canonical by their listener, may be nil
+	extendCall bool                    // Extend extrinsic calls to substrate with ResourceID.Used for backward compatibility with example pallet.
+	queue      *msgqueue.Queue         // Durable record of received messages, may be nil
+	drainer    *chains.Drainer         // Tracks pending votes so they can complete on shutdown
 }
 
 func NewWriter(conn *Connection, log log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics, extendCall bool) *writer {
@@ -38,13 +55,96 @@ func NewWriter(conn *Connection, log log15.Logger, sysErr chan<- error, m *metri
 		sysErr:     sysErr,
 		metrics:    m,
 		extendCall: extendCall,
+		amounts:    amount.NewConverter(nil, amount.DefaultDustPolicy),
+		drainer:    chains.NewDrainer(log, "extrinsics"),
+	}
+}
+
+func (w *writer) start() error {
+	if w.queue != nil {
+		go w.replayMessages()
+	}
+	return nil
+}
+
+// setAmounts sets the converter of fungible amounts
+func (w *writer) setAmounts(c *amount.Converter) {
+	w.amounts = c
+}
+
+// setPolicy sets the policy messages are checked against before voting, which is optional
+func (w *writer) setPolicy(p *policy.Policy) {
+	w.policy = p
+}
+
+// setVerifier sets the verifier deposits are re-checked with before voting, which is optional
+func (w *writer) setVerifier(v *chains.Verifier) {
+	w.verifier = v
+}
+
+// setReorgedDeposits sets the deposits the writer refuses to vote on as they are no longer on the canonical chain
+func (w *writer) setReorgedDeposits(r *chains.ReorgedDeposits) {
+	w.reorged = r
+}
+
+// setLifecycleMetrics sets the message lifecycle metrics, which are optional
+func (w *writer) setLifecycleMetrics(m *chains.LifecycleMetrics) {
+	w.lifecycle = m
+}
+
+// setQueue adds the message queue used to persist in-flight messages
+func (w *writer) setQueue(q *msgqueue.Queue) {
+	w.queue = q
+}
+
+// replayMessages resolves every message that was received but not yet voted on, eg. before a restart.
+// The pallet executes proposals itself, so nothing is left to do for messages that have been voted on.
+func (w *writer) replayMessages() {
+	for _, e := range w.queue.Entries(msgqueue.Received) {
+		w.messageLog(e.Message).Info("Replaying unfinished message", "src", e.Message.Source, "nonce", e.Message.DepositNonce)
+		w.ResolveMessage(e.Message)
+	}
+}
+
+// resubmit resolves a message from the queue again, eg. one that failed. The message is resolved in the background.
+func (w *writer) resubmit(m msg.Message) {
+	w.messageLog(m).Info("Re-submitting message", "src", m.Source, "nonce", m.DepositNonce)
+	w.setMessageState(m, msgqueue.Received)
+	go w.ResolveMessage(m)
+}
+
+// messageLog returns the writer logger with the correlation ID of m
+func (w *writer) messageLog(m msg.Message) log15.Logger {
+	return chains.MessageLogger(w.log, m)
+}
+
+// setMessageState records the state of a message in the queue, if one is configured
+func (w *writer) setMessageState(m msg.Message, s msgqueue.State) {
+	if w.queue == nil {
+		return
+	}
+	err := w.queue.SetState(m, s)
+	if err != nil {
+		w.messageLog(m).Error("Failed to update message queue", "src", m.Source, "nonce", m.DepositNonce, "state", s, "err", err)
 	}
 }
 
+// checks returns the checks messages must pass before the writer votes on them
+func (w *writer) checks() chains.Checks {
+	return chains.Checks{Queue: w.queue, Policy: w.policy, Verifier: w.verifier, Reorged: w.reorged}
+}
+
 func (w *writer) ResolveMessage(m msg.Message) bool {
+	log := w.messageLog(m)
 	var prop *proposal
 	var err error
 
+	done := w.drainer.Admit(m, "resolve", w.checks(), log)
+	if done == nil {
+		return false
+	}
+	defer done()
+
 	// Construct the proposal
 	switch m.Type {
 	case msg.FungibleTransfer:
@@ -54,45 +154,65 @@ func (w *writer) ResolveMessage(m msg.Message) bool {
 	case msg.GenericTransfer:
 		prop, err = w.createGenericProposal(m)
 	default:
+		w.setMessageState(m, msgqueue.Failed)
 		w.sysErr <- fmt.Errorf("unrecognized message type received (chain=%d, name=%s)", m.Destination, w.conn.name)
 		return false
 	}
 
-	if err != nil {
+	if errors.Is(err, amount.ErrDust) {
+		log.Error("Rejected transfer", "err", err)
+		w.setMessageState(m, msgqueue.Failed)
+		return false
+	} else if err != nil {
+		w.setMessageState(m, msgqueue.Failed)
 		w.sysErr <- fmt.Errorf("failed to construct proposal (chain=%d, name=%s) Error: %w", m.Destination, w.conn.name, err)
 		return false
 	}
 
+	created := false
 	for i := 0; i < BlockRetryLimit; i++ {
 		// Ensure we only submit a vote if the proposal hasn't completed
 		valid, reason, err := w.proposalValid(prop)
 		if err != nil {
-			w.log.Error("Failed to assert proposal state", "err", err)
+			log.Error("Failed to assert proposal state", "err", err)
 			time.Sleep(BlockRetryInterval)
 			continue
 		}
 
 		// If active submit call, otherwise skip it. Retry on failure.
 		if valid {
-			w.log.Info("Acknowledging proposal on chain", "nonce", prop.depositNonce, "source", prop.sourceId, "resource", fmt.Sprintf("%x", prop.resourceId), "method", prop.method)
+			if !created {
+				w.lifecycle.ProposalCreated(m)
+				created = true
+			}
+			log.Info("Acknowledging proposal on chain", "nonce", prop.depositNonce, "source", prop.sourceId, "resource", fmt.Sprintf("%x", prop.resourceId), "method", prop.method)
 
-			err = w.conn.SubmitTx(AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
+			err = w.conn.submitTx(log, AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
 			if err != nil && err.Error() == TerminatedError.Error() {
 				return false
 			} else if err != nil {
-				w.log.Error("Failed to execute extrinsic", "err", err)
+				log.Error("Failed to execute extrinsic", "err", err)
 				time.Sleep(BlockRetryInterval)
 				continue
 			}
+			w.lifecycle.Voted(m, chains.ResultMined)
 			if w.metrics != nil {
 				w.metrics.VotesSubmitted.Inc()
 			}
+			w.setMessageState(m, msgqueue.Voted)
 			return true
 		} else {
-			w.log.Info("Ignoring proposal", "reason", reason, "nonce", prop.depositNonce, "source", prop.sourceId, "resource", prop.resourceId)
+			log.Info("Ignoring proposal", "reason", reason, "nonce", prop.depositNonce, "source", prop.sourceId, "resource", prop.resourceId)
+			if reason == ReasonProposalComplete {
+				w.setMessageState(m, msgqueue.Executed)
+			} else {
+				w.setMessageState(m, msgqueue.Voted)
+			}
 			return true
 		}
 	}
+	w.lifecycle.Voted(m, chains.ResultFailed)
+	w.setMessageState(m, msgqueue.Failed)
 	return true
 }
 
@@ -130,12 +250,12 @@ func (w *writer) proposalValid(prop *proposal) (bool, string, error) {
 	} else if voteRes.Status.IsActive {
 		if containsVote(voteRes.VotesFor, types.NewAccountID(w.conn.key.PublicKey)) ||
 			containsVote(voteRes.VotesAgainst, types.NewAccountID(w.conn.key.PublicKey)) {
-			return false, "already voted", nil
+			return false, ReasonAlreadyVoted, nil
 		} else {
 			return true, "", nil
 		}
 	} else {
-		return false, "proposal complete", nil
+		return false, ReasonProposalComplete, nil
 	}
 }
 
@@ -4,6 +4,7 @@
 package substrate
 
 import (
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/centrifuge/go-substrate-rpc-client/types"
 
 	message "github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/amount"
 	utils "github.com/UltronFoundationDev/chainbridge/shared/substrate"
 	subtest "github.com/UltronFoundationDev/chainbridge/shared/substrate/testing"
 )
@@ -116,6 +118,28 @@ func TestWriter_ResolveMessage_FungibleProposal(t *testing.T) {
 	}
 }
 
+func TestWriter_IncomingAmount(t *testing.T) {
+	rId := message.ResourceIdFromSlice([]byte{1})
+	// Decimals of this chain with 12 decimals, for transfers to and from an ERC20 with 18
+	decimals := map[message.ChainId]map[string][2]uint8{ForeignChain: {rId.Hex(): {12, 18}}}
+	eighteen, _ := big.NewInt(0).SetString("1500000000000000001", 10)
+
+	w := &writer{log: AliceTestLogger, amounts: amount.NewConverter(decimals, amount.DustTruncate)}
+	amt, err := w.incomingAmount(message.NewFungibleTransfer(ForeignChain, ThisChain, 1, eighteen, rId, BobKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amt.Cmp(big.NewInt(1500000000000)) != 0 {
+		t.Fatalf("expected incoming amount of 1500000000000, got %s", amt)
+	}
+
+	w.amounts = amount.NewConverter(decimals, amount.DustReject)
+	_, err = w.incomingAmount(message.NewFungibleTransfer(ForeignChain, ThisChain, 1, eighteen, rId, BobKey.PublicKey))
+	if !errors.Is(err, amount.ErrDust) {
+		t.Fatalf("expected ErrDust, got %v", err)
+	}
+}
+
 func TestWriter_ResolveMessage_NonFungibleProposal(t *testing.T) {
 	// Setup message and params
 	var rId [32]byte
@@ -0,0 +1,124 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+// VerifyTimeout is how long a writer waits for a deposit to reach the required confirmations before giving up
+var VerifyTimeout = time.Minute * 5
+
+// VerifyRetryInterval is the time between attempts to verify a deposit that is not yet confirmed
+var VerifyRetryInterval = time.Second * 5
+
+// ErrUnconfirmed is returned by a DepositVerifier for deposits without the required confirmations
+var ErrUnconfirmed = errors.New("deposit does not have the required confirmations")
+
+// ErrVerifyStopped is returned by Verify if it is stopped while waiting for a deposit to be confirmed
+var ErrVerifyStopped = errors.New("verification stopped")
+
+// ErrDepositMismatch is wrapped by errors for deposits on the source chain that differ from the message
+var ErrDepositMismatch = errors.New("deposit does not match message")
+
+// DepositVerifier re-reads the deposits made on a chain
+type DepositVerifier interface {
+	// VerifyDeposit returns nil if the deposit of m is in a canonical block with the required confirmations and
+	// matches m
+	VerifyDeposit(m msg.Message) error
+}
+
+// Verifier verifies messages with the DepositVerifier of their source chain. A single instance is shared by all
+// chains, verifiers must be added before the chains are started.
+type Verifier struct {
+	verifiers map[msg.ChainId]DepositVerifier
+}
+
+func NewVerifier() *Verifier {
+	return &Verifier{verifiers: make(map[msg.ChainId]DepositVerifier)}
+}
+
+// Add sets the verifier of the deposits made on chain id
+func (v *Verifier) Add(id msg.ChainId, dv DepositVerifier) {
+	v.verifiers[id] = dv
+}
+
+// Verify verifies the deposit of m on its source chain, waiting up to VerifyTimeout for it to be confirmed. Waiting
+// ends with ErrVerifyStopped once stop is closed.
+func (v *Verifier) Verify(m msg.Message, stop <-chan struct{}) error {
+	dv, ok := v.verifiers[m.Source]
+	if !ok {
+		return fmt.Errorf("source chain %d cannot be verified", m.Source)
+	}
+
+	deadline := time.Now().Add(VerifyTimeout)
+	for {
+		err := dv.VerifyDeposit(m)
+		if !errors.Is(err, ErrUnconfirmed) || time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-stop:
+			return ErrVerifyStopped
+		case <-time.After(VerifyRetryInterval):
+		}
+	}
+}
+
+// MatchDeposit compares m with the message constructed from its deposit on the source chain. The error wraps
+// ErrDepositMismatch and names the first field that differs.
+func MatchDeposit(m, deposit msg.Message) error {
+	mismatch := func(field string, expected, got interface{}) error {
+		return fmt.Errorf("%w: %s is %v on the source chain, message has %v", ErrDepositMismatch, field, got, expected)
+	}
+
+	if m.Source != deposit.Source || m.Destination != deposit.Destination || m.DepositNonce != deposit.DepositNonce {
+		return mismatch("deposit", CorrelationID(m.Source, m.Destination, m.DepositNonce), CorrelationID(deposit.Source, deposit.Destination, deposit.DepositNonce))
+	}
+	if m.Type != deposit.Type {
+		return mismatch("type", m.Type, deposit.Type)
+	}
+	if m.ResourceId != deposit.ResourceId {
+		return mismatch("resource ID", m.ResourceId.Hex(), deposit.ResourceId.Hex())
+	}
+	if len(m.Payload) != len(deposit.Payload) {
+		return mismatch("payload length", len(m.Payload), len(deposit.Payload))
+	}
+
+	var fields []string
+	switch m.Type {
+	case msg.FungibleTransfer:
+		fields = []string{"amount", "recipient"}
+	case msg.NonFungibleTransfer:
+		fields = []string{"token ID", "recipient", "metadata"}
+	case msg.GenericTransfer:
+		fields = []string{"metadata"}
+	}
+	for i := range m.Payload {
+		expected, ok := m.Payload[i].([]byte)
+		got, ok2 := deposit.Payload[i].([]byte)
+		if !ok || !ok2 {
+			return fmt.Errorf("unsupported payload types %T and %T", m.Payload[i], deposit.Payload[i])
+		}
+		field := fmt.Sprintf("payload %d", i)
+		if i < len(fields) {
+			field = fields[i]
+		}
+		// Amounts and token IDs may be encoded with leading zeros
+		if field == "amount" || field == "token ID" {
+			if new(big.Int).SetBytes(expected).Cmp(new(big.Int).SetBytes(got)) != 0 {
+				return mismatch(field, new(big.Int).SetBytes(expected), new(big.Int).SetBytes(got))
+			}
+		} else if !bytes.Equal(expected, got) {
+			return mismatch(field, fmt.Sprintf("0x%x", expected), fmt.Sprintf("0x%x", got))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+)
+
+var verifyResource = msg.ResourceIdFromSlice([]byte{1})
+
+func TestMatchDeposit(t *testing.T) {
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab})
+
+	// Amounts may be encoded with leading zeros
+	deposit := m
+	deposit.Payload = []interface{}{append([]byte{0}, big.NewInt(100).Bytes()...), []byte{0xab}}
+	if err := MatchDeposit(m, deposit); err != nil {
+		t.Fatal(err)
+	}
+
+	nft := msg.NewNonFungibleTransfer(1, 2, 3, verifyResource, big.NewInt(7), []byte{0xab}, []byte{0x1})
+	for name, deposit := range map[string]msg.Message{
+		"amount":      msg.NewFungibleTransfer(1, 2, 3, big.NewInt(101), verifyResource, []byte{0xab}),
+		"recipient":   msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xac}),
+		"resource ID": msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), msg.ResourceIdFromSlice([]byte{2}), []byte{0xab}),
+		"nonce":       msg.NewFungibleTransfer(1, 2, 4, big.NewInt(100), verifyResource, []byte{0xab}),
+		"type":        msg.NewGenericTransfer(1, 2, 3, verifyResource, []byte{0xab}),
+		"metadata":    msg.NewNonFungibleTransfer(1, 2, 3, verifyResource, big.NewInt(7), []byte{0xab}, []byte{0x2}),
+	} {
+		expected := m
+		if name == "metadata" {
+			expected = nft
+		}
+		if err := MatchDeposit(expected, deposit); !errors.Is(err, ErrDepositMismatch) {
+			t.Errorf("%s: expected mismatch, got %v", name, err)
+		}
+	}
+}
+
+type testVerifier struct {
+	errs  []error
+	calls int
+}
+
+func (v *testVerifier) VerifyDeposit(m msg.Message) error {
+	v.calls++
+	if len(v.errs) == 0 {
+		return nil
+	}
+	err := v.errs[0]
+	v.errs = v.errs[1:]
+	return err
+}
+
+func TestVerifier(t *testing.T) {
+	interval, timeout := VerifyRetryInterval, VerifyTimeout
+	defer func() { VerifyRetryInterval, VerifyTimeout = interval, timeout }()
+	VerifyRetryInterval = time.Millisecond
+	VerifyTimeout = time.Second
+
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(100), verifyResource, []byte{0xab})
+	dv := &testVerifier{errs: []error{ErrUnconfirmed, ErrUnconfirmed}}
+	v := NewVerifier()
+	v.Add(1, dv)
+
+	// Unconfirmed deposits are retried
+	if err := v.Verify(m, nil); err != nil || dv.calls != 3 {
+		t.Fatalf("expected verification after 3 attempts, got %v after %d", err, dv.calls)
+	}
+
+	// Other errors are returned immediately
+	dv.errs, dv.calls = []error{ErrDepositMismatch}, 0
+	if err := v.Verify(m, nil); !errors.Is(err, ErrDepositMismatch) || dv.calls != 1 {
+		t.Fatalf("expected mismatch after 1 attempt, got %v after %d", err, dv.calls)
+	}
+
+	// Deposits that stay unconfirmed fail after the timeout
+	VerifyTimeout = time.Millisecond * 20
+	dv.errs = make([]error, 1000)
+	for i := range dv.errs {
+		dv.errs[i] = ErrUnconfirmed
+	}
+	if err := v.Verify(m, nil); !errors.Is(err, ErrUnconfirmed) {
+		t.Fatalf("expected unconfirmed error, got %v", err)
+	}
+
+	// Waiting ends when the verifier is stopped
+	VerifyTimeout = time.Minute
+	stop := make(chan struct{})
+	close(stop)
+	if err := v.Verify(m, stop); !errors.Is(err, ErrVerifyStopped) {
+		t.Fatalf("expected stopped error, got %v", err)
+	}
+
+	// Deposits from unknown chains cannot be verified
+	m.Source = 5
+	if err := v.Verify(m, nil); err == nil {
+		t.Fatal("expected error for unknown source chain")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+)
+
+// Health statuses added to a successful health check
+const (
+	HealthOk       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// balanceMonitored is implemented by chains that monitor the relayer balance
+type balanceMonitored interface {
+	Id() msg.ChainId
+	BalanceMonitor() *chains.BalanceMonitor
+}
+
+type lowBalance struct {
+	ChainId   msg.ChainId `json:"chainId"`
+	Balance   string      `json:"balance"`
+	Threshold string      `json:"threshold"`
+}
+
+// balanceHealth adds a status to successful responses of the health check next. The status is degraded, with the
+// chains listed under lowBalances, while any relayer balance is below its threshold.
+func balanceHealth(next http.HandlerFunc, monitored []balanceMonitored) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		next(rec, r)
+
+		var body map[string]interface{}
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &body) != nil {
+			for k, v := range rec.Header() {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(rec.Body.Bytes())
+			return
+		}
+
+		var low []lowBalance
+		for _, c := range monitored {
+			if status := c.BalanceMonitor().Status(); status.Low {
+				low = append(low, lowBalance{ChainId: c.Id(), Balance: status.Balance.String(), Threshold: status.Threshold.String()})
+			}
+		}
+		body["status"] = HealthOk
+		if len(low) != 0 {
+			body["status"] = HealthDegraded
+			body["lowBalances"] = low
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains"
+)
+
+type testMonitored struct {
+	id      msg.ChainId
+	monitor *chains.BalanceMonitor
+}
+
+func (t testMonitored) Id() msg.ChainId                        { return t.id }
+func (t testMonitored) BalanceMonitor() *chains.BalanceMonitor { return t.monitor }
+
+// newTestMonitor returns a monitor that has fetched balance
+func newTestMonitor(t *testing.T, balance, threshold int64, stop chan int) *chains.BalanceMonitor {
+	m := chains.NewBalanceMonitor(func() (*big.Int, error) { return big.NewInt(balance), nil }, big.NewInt(threshold), log15.Root(), stop)
+	m.Start()
+	for i := 0; m.Status().Balance == nil; i++ {
+		if i == 100 {
+			t.Fatal("balance was not fetched")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	return m
+}
+
+func TestBalanceHealth(t *testing.T) {
+	stop := make(chan int)
+	defer close(stop)
+	monitored := []balanceMonitored{
+		testMonitored{id: 1, monitor: newTestMonitor(t, 100, 50, stop)},
+		testMonitored{id: 2, monitor: newTestMonitor(t, 10, 50, stop)},
+	}
+	upstream := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"chains":[{"chainId":1,"height":10}]}`))
+	}
+
+	rec := httptest.NewRecorder()
+	balanceHealth(upstream, monitored)(rec, httptest.NewRequest("GET", "/health", nil))
+
+	var body struct {
+		Chains      []interface{} `json:"chains"`
+		Status      string        `json:"status"`
+		LowBalances []lowBalance  `json:"lowBalances"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != HealthDegraded || len(body.Chains) != 1 {
+		t.Fatalf("expected degraded status with the upstream chains, got %s", rec.Body.String())
+	}
+	if len(body.LowBalances) != 1 || body.LowBalances[0] != (lowBalance{ChainId: 2, Balance: "10", Threshold: "50"}) {
+		t.Fatalf("unexpected low balances %+v", body.LowBalances)
+	}
+
+	rec = httptest.NewRecorder()
+	balanceHealth(upstream, monitored[:1])(rec, httptest.NewRequest("GET", "/health", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != HealthOk {
+		t.Fatalf("expected ok status, got %s", rec.Body.String())
+	}
+
+	// Errors are passed through unchanged
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":"chain 1 stalled"}`))
+	}
+	rec = httptest.NewRecorder()
+	balanceHealth(failing, monitored)(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != `{"error":"chain 1 stalled"}` {
+		t.Fatalf("expected upstream error, got %d %s", rec.Code, rec.Body.String())
+	}
+}
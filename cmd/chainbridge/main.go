@@ -12,15 +12,22 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 
 	"strconv"
+	"syscall"
+	"time"
 
 	log "github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/core"
 	"github.com/UltronFoundationDev/chainbridge-utils/metrics/health"
 	metrics "github.com/UltronFoundationDev/chainbridge-utils/metrics/types"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/admin"
+	"github.com/UltronFoundationDev/chainbridge/chains"
 	"github.com/UltronFoundationDev/chainbridge/chains/ethereum"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
 	"github.com/UltronFoundationDev/chainbridge/chains/substrate"
 	"github.com/UltronFoundationDev/chainbridge/config"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -29,15 +36,39 @@ import (
 
 var app = cli.NewApp()
 
+var _ admin.Chain = &ethereum.Chain{}
+var _ admin.Chain = &substrate.Chain{}
+var _ balanceMonitored = &ethereum.Chain{}
+var _ balanceMonitored = &substrate.Chain{}
+var _ lifecycleMonitored = &ethereum.Chain{}
+var _ lifecycleMonitored = &substrate.Chain{}
+var _ limited = &ethereum.Chain{}
+var _ limited = &substrate.Chain{}
+var _ policed = &ethereum.Chain{}
+var _ policed = &substrate.Chain{}
+var _ verifying = &ethereum.Chain{}
+var _ verifying = &substrate.Chain{}
+var _ queued = &ethereum.Chain{}
+var _ queued = &substrate.Chain{}
+var _ reorgTracked = &ethereum.Chain{}
+var _ reorgTracked = &substrate.Chain{}
+var _ drainer = &ethereum.Chain{}
+var _ drainer = &substrate.Chain{}
+
 var cliFlags = []cli.Flag{
 	config.ConfigFileFlag,
 	config.VerbosityFlag,
+	config.JSONLogFlag,
 	config.KeystorePathFlag,
 	config.BlockstorePathFlag,
 	config.FreshStartFlag,
 	config.LatestBlockFlag,
+	config.ShutdownTimeoutFlag,
+	config.PolicyFileFlag,
 	config.MetricsFlag,
 	config.MetricsPort,
+	config.AdminFlag,
+	config.AdminPortFlag,
 }
 
 var generateFlags = []cli.Flag{
@@ -112,6 +143,8 @@ func init() {
 	app.EnableBashCompletion = true
 	app.Commands = []*cli.Command{
 		&accountCommand,
+		&configCommand,
+		&replayCommand,
 	}
 
 	app.Flags = append(app.Flags, cliFlags...)
@@ -128,6 +161,9 @@ func main() {
 func startLogger(ctx *cli.Context) error {
 	logger := log.Root()
 	handler := logger.GetHandler()
+	if ctx.Bool(config.JSONLogFlag.Name) {
+		handler = log.StreamHandler(os.Stdout, log.JsonFormat())
+	}
 	var lvl log.Lvl
 
 	if lvlToInt, err := strconv.Atoi(ctx.String(config.VerbosityFlag.Name)); err == nil {
@@ -140,6 +176,120 @@ func startLogger(ctx *cli.Context) error {
 	return nil
 }
 
+// keystorePath returns the keystore to load keys from, which is insecure if a test key is used
+func keystorePath(ctx *cli.Context, cfg *config.Config) (string, bool) {
+	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
+		return key, true
+	}
+	return cfg.KeystorePath, false
+}
+
+// newChainConfig constructs the core.ChainConfig for a chain of the config file
+func newChainConfig(ctx *cli.Context, chain config.RawChainConfig, keystorePath string, insecure bool) (*core.ChainConfig, error) {
+	chainId, err := strconv.Atoi(chain.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ChainConfig{
+		Name:           chain.Name,
+		Id:             msg.ChainId(chainId),
+		Endpoint:       chain.Endpoint,
+		From:           chain.From,
+		KeystorePath:   keystorePath,
+		Insecure:       insecure,
+		BlockstorePath: ctx.String(config.BlockstorePathFlag.Name),
+		FreshStart:     ctx.Bool(config.FreshStartFlag.Name),
+		LatestBlock:    ctx.Bool(config.LatestBlockFlag.Name),
+		Opts:           chain.Opts,
+		Decimals:       chain.Decimals,
+	}, nil
+}
+
+// initializeChain initializes a chain of the given type
+func initializeChain(chainType string, chainConfig *core.ChainConfig, logger log.Logger, sysErr chan<- error, m *metrics.ChainMetrics) (core.Chain, error) {
+	switch chainType {
+	case "ethereum":
+		return ethereum.InitializeChain(chainConfig, logger, sysErr, m)
+	case "substrate":
+		return substrate.InitializeChain(chainConfig, logger, sysErr, m)
+	default:
+		return nil, errors.New("unrecognized Chain Type")
+	}
+}
+
+// lifecycleMonitored is implemented by chains that record the message lifecycle metrics
+type lifecycleMonitored interface {
+	SetLifecycleMetrics(m *chains.LifecycleMetrics)
+}
+
+// limited is implemented by chains whose transfers can be checked against limits before they are routed
+type limited interface {
+	SetTransferGate(g *limits.Gate)
+}
+
+// newTransferGate creates the gate checking the transfers of a chain against its limits, or nil if it has none
+func newTransferGate(chain config.RawChainConfig, chainConfig *core.ChainConfig, logger log.Logger) (*limits.Gate, error) {
+	if len(chain.Limits) == 0 {
+		return nil, nil
+	}
+	l, err := limits.Parse(chain.Limits)
+	if err != nil {
+		return nil, fmt.Errorf("chain %s: %w", chain.Name, err)
+	}
+	q, err := limits.NewQuarantine(chainConfig.BlockstorePath, chainConfig.Id, chainConfig.From)
+	if err != nil {
+		return nil, err
+	}
+	w, err := limits.NewWindow(chainConfig.BlockstorePath, chainConfig.Id, chainConfig.From)
+	if err != nil {
+		return nil, err
+	}
+	return limits.NewGate(l, q, w, logger), nil
+}
+
+// policed is implemented by chains whose writer checks messages against the policy before voting
+type policed interface {
+	SetPolicy(p *policy.Policy)
+}
+
+// verifying is implemented by chains whose deposits can be verified and whose writer can verify deposits before voting
+type verifying interface {
+	DepositVerifier() chains.DepositVerifier
+	SetVerifier(v *chains.Verifier)
+}
+
+// reorgTracked is implemented by chains whose writer refuses deposits that are no longer on the canonical chain
+type reorgTracked interface {
+	SetReorgedDeposits(r *chains.ReorgedDeposits)
+}
+
+// loadPolicy loads the policy file given by the cli context, or returns nil if there is none
+func loadPolicy(ctx *cli.Context) (*policy.Policy, error) {
+	path := ctx.String(config.PolicyFileFlag.Name)
+	if path == "" {
+		return nil, nil
+	}
+	return policy.Load(path, log.Root().New("module", "policy"))
+}
+
+// drainer is implemented by chains that wait for pending work when stopped
+type drainer interface {
+	SetShutdownTimeout(d time.Duration)
+	StopListener()
+}
+
+// stopListenersOnSignal stops the listeners of every chain as soon as a shutdown signal is received. Core stops the
+// chains one at a time, so this ensures no chain routes new messages while another chain's writer is draining.
+func stopListenersOnSignal(chains []drainer) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	signal.Stop(sigc)
+	for _, d := range chains {
+		d.StopListener()
+	}
+}
+
 func run(ctx *cli.Context) error {
 	err := startLogger(ctx)
 	if err != nil {
@@ -155,40 +305,37 @@ func run(ctx *cli.Context) error {
 
 	log.Debug("Config on initialization...", "config", *cfg)
 
-	// Check for test key flag
-	var ks string
-	var insecure bool
-	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
-		ks = key
-		insecure = true
-	} else {
-		ks = cfg.KeystorePath
-	}
+	ks, insecure := keystorePath(ctx, cfg)
 
 	// Used to signal core shutdown due to fatal error
 	sysErr := make(chan error)
 	c := core.NewCore(sysErr)
+	reloaders := make(map[msg.ChainId]reloader)
+	var drainers []drainer
+	var adminChains []admin.Chain
+	var monitored []balanceMonitored
+	var lifecycle *chains.LifecycleMetrics
+	verifier := chains.NewVerifier()
+	reorged := chains.NewReorgedDeposits()
+	if ctx.Bool(config.MetricsFlag.Name) {
+		lifecycle = chains.NewLifecycleMetrics()
+	}
+	p, err := loadPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(config.MetricsFlag.Name) {
+		p.RegisterMetrics()
+	}
 
 	for _, chain := range cfg.Chains {
-		chainId, errr := strconv.Atoi(chain.Id)
+		chainConfig, errr := newChainConfig(ctx, chain, ks, insecure)
 		if errr != nil {
 			return errr
 		}
-		chainConfig := &core.ChainConfig{
-			Name:           chain.Name,
-			Id:             msg.ChainId(chainId),
-			Endpoint:       chain.Endpoint,
-			From:           chain.From,
-			KeystorePath:   ks,
-			Insecure:       insecure,
-			BlockstorePath: ctx.String(config.BlockstorePathFlag.Name),
-			FreshStart:     ctx.Bool(config.FreshStartFlag.Name),
-			LatestBlock:    ctx.Bool(config.LatestBlockFlag.Name),
-			Opts:           chain.Opts,
-			Decimals:       chain.Decimals,
-		}
 		var newChain core.Chain
 		var m *metrics.ChainMetrics
+		var gate *limits.Gate
 
 		logger := log.Root().New("chain", chainConfig.Name)
 
@@ -196,18 +343,47 @@ func run(ctx *cli.Context) error {
 			m = metrics.NewChainMetrics(chain.Name)
 		}
 
-		if chain.Type == "ethereum" {
-			newChain, err = ethereum.InitializeChain(chainConfig, logger, sysErr, m)
-		} else if chain.Type == "substrate" {
-			newChain, err = substrate.InitializeChain(chainConfig, logger, sysErr, m)
-		} else {
-			return errors.New("unrecognized Chain Type")
+		newChain, err = initializeChain(chain.Type, chainConfig, logger, sysErr, m)
+		if err != nil {
+			return err
 		}
-
+		// The gate must be set before the chain is added, as adding it sets the router
+		gate, err = newTransferGate(chain, chainConfig, logger)
 		if err != nil {
 			return err
 		}
+		if l, ok := newChain.(limited); ok && gate != nil {
+			l.SetTransferGate(gate)
+		}
 		c.AddChain(newChain)
+		if d, ok := newChain.(drainer); ok {
+			d.SetShutdownTimeout(ctx.Duration(config.ShutdownTimeoutFlag.Name))
+			drainers = append(drainers, d)
+		}
+		if a, ok := newChain.(admin.Chain); ok {
+			adminChains = append(adminChains, a)
+		}
+		if r, ok := newChain.(reloader); ok {
+			reloaders[chainConfig.Id] = r
+		}
+		if b, ok := newChain.(balanceMonitored); ok {
+			monitored = append(monitored, b)
+		}
+		if l, ok := newChain.(lifecycleMonitored); ok && lifecycle != nil {
+			l.SetLifecycleMetrics(lifecycle)
+		}
+		if pc, ok := newChain.(policed); ok && p != nil {
+			pc.SetPolicy(p)
+		}
+		// Writers may verify deposits made on any chain, so all chains share the verifier
+		if v, ok := newChain.(verifying); ok {
+			verifier.Add(chainConfig.Id, v.DepositVerifier())
+			v.SetVerifier(verifier)
+		}
+		// Deposits are flagged by the listener of their source chain, but checked by the writer of the destination
+		if r, ok := newChain.(reorgTracked); ok {
+			r.SetReorgedDeposits(reorged)
+		}
 
 	}
 
@@ -226,7 +402,7 @@ func run(ctx *cli.Context) error {
 
 		go func() {
 			http.Handle("/metrics", promhttp.Handler())
-			http.HandleFunc("/health", h.HealthStatus)
+			http.HandleFunc("/health", balanceHealth(h.HealthStatus, monitored))
 			err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
 			if errors.Is(err, http.ErrServerClosed) {
 				log.Info("Health status server is shutting down", err)
@@ -236,6 +412,27 @@ func run(ctx *cli.Context) error {
 		}()
 	}
 
+	// Start admin API server
+	if ctx.Bool(config.AdminFlag.Name) {
+		h, err := admin.NewHandler(os.Getenv(config.AdminToken), adminChains)
+		if err != nil {
+			return err
+		}
+		addr := fmt.Sprintf("127.0.0.1:%d", ctx.Int(config.AdminPortFlag.Name))
+		log.Info("Starting admin API", "addr", addr)
+
+		go func() {
+			err := http.ListenAndServe(addr, h)
+			log.Error("Error serving admin API", "err", err)
+		}()
+	}
+
+	go watchConfig(ctx, reloaders, ks, insecure)
+	if p != nil {
+		go watchPolicy(p)
+	}
+	go stopListenersOnSignal(drainers)
+
 	c.Start()
 
 	return nil
@@ -0,0 +1,102 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/policy"
+	"github.com/UltronFoundationDev/chainbridge/config"
+	"github.com/urfave/cli/v2"
+)
+
+// ConfigPollInterval is how often the config and policy files are checked for modifications
+var ConfigPollInterval = time.Second * 5
+
+// reloader is implemented by chains that can apply config changes while running
+type reloader interface {
+	Reload(chainCfg *core.ChainConfig) error
+}
+
+// watchConfig reloads the config when the file is modified or SIGHUP is received. It never returns.
+func watchConfig(ctx *cli.Context, chains map[msg.ChainId]reloader, keystorePath string, insecure bool) {
+	watchFile(config.Path(ctx), "config", func() {
+		reloadConfig(ctx, chains, keystorePath, insecure)
+	})
+}
+
+// watchPolicy reloads the policy when the file is modified or SIGHUP is received. It never returns.
+func watchPolicy(p *policy.Policy) {
+	watchFile(p.Path(), "policy", func() {
+		err := p.Reload()
+		if err != nil {
+			log.Error("Failed to reload policy, keeping the current rules", "path", p.Path(), "err", err)
+		}
+	})
+}
+
+// watchFile calls reload when the file at path is modified or SIGHUP is received. It never returns.
+func watchFile(path string, name string, reload func()) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	modTime := fileModTime(path)
+	ticker := time.NewTicker(ConfigPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sighup:
+			log.Info("Received SIGHUP, reloading "+name, "path", path)
+		case <-ticker.C:
+			latest := fileModTime(path)
+			if latest.Equal(modTime) {
+				continue
+			}
+			modTime = latest
+			log.Info("File modified, reloading "+name, "path", path)
+		}
+		reload()
+	}
+}
+
+// reloadConfig reads the config and applies it to each running chain. A chain whose changes cannot be applied keeps
+// its current options.
+func reloadConfig(ctx *cli.Context, chains map[msg.ChainId]reloader, keystorePath string, insecure bool) {
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		log.Error("Failed to reload config", "err", err)
+		return
+	}
+
+	for _, chain := range cfg.Chains {
+		chainConfig, err := newChainConfig(ctx, chain, keystorePath, insecure)
+		if err != nil {
+			log.Error("Failed to reload config", "chain", chain.Name, "err", err)
+			continue
+		}
+		r, ok := chains[chainConfig.Id]
+		if !ok {
+			log.Warn("Chain does not support reloading, changes require a restart", "chain", chain.Name, "id", chain.Id)
+			continue
+		}
+		err = r.Reload(chainConfig)
+		if err != nil {
+			log.Error("Rejected config changes", "chain", chain.Name, "err", err)
+		}
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
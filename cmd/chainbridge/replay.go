@@ -0,0 +1,241 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/ethereum"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/substrate"
+	"github.com/UltronFoundationDev/chainbridge/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+var replayCommand = cli.Command{
+	Action: handleReplayCmd,
+	Name:   "replay",
+	Usage:  "replay the deposits of a block or transaction",
+	Flags: []cli.Flag{
+		config.ConfigFileFlag,
+		config.VerbosityFlag,
+		config.JSONLogFlag,
+		config.KeystorePathFlag,
+		config.BlockstorePathFlag,
+		config.TestKeyFlag,
+		config.ReplayChainFlag,
+		config.ReplayBlockFlag,
+		config.ReplayTxFlag,
+		config.DryRunFlag,
+		config.PolicyFileFlag,
+	},
+	Description: "The replay command parses the deposits of a single block or transaction on the source chain and\n" +
+		"\tsubmits the resulting messages to their destination writers once.\n" +
+		"\tTo replay a block: chainbridge replay --chain <id> --block <n>\n" +
+		"\tTo replay a transaction: chainbridge replay --chain <id> --tx <hash>\n" +
+		"\tUse --dryRun to only print the messages.",
+}
+
+// collector is a chains.Router that records the messages routed by a listener
+type collector struct {
+	msgs []msg.Message
+}
+
+func (c *collector) Send(m msg.Message) error {
+	c.msgs = append(c.msgs, m)
+	return nil
+}
+
+func handleReplayCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	hasTx, err := replayByTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	ks, insecure := keystorePath(ctx, cfg)
+
+	chains := make(map[msg.ChainId]config.RawChainConfig)
+	chainConfigs := make(map[msg.ChainId]*core.ChainConfig)
+	for _, chain := range cfg.Chains {
+		chainConfig, err := newChainConfig(ctx, chain, ks, insecure)
+		if err != nil {
+			return err
+		}
+		chains[chainConfig.Id] = chain
+		chainConfigs[chainConfig.Id] = chainConfig
+	}
+
+	src := msg.ChainId(ctx.Uint(config.ReplayChainFlag.Name))
+	srcChain, ok := chains[src]
+	if !ok {
+		return fmt.Errorf("chain %d is not in the config", src)
+	}
+	logger := log.Root().New("chain", srcChain.Name)
+
+	// Collect the messages first, so only their destinations need to be initialized
+	c := &collector{}
+	switch srcChain.Type {
+	case "ethereum":
+		var txHash *common.Hash
+		var block *big.Int
+		if hasTx {
+			hash := common.HexToHash(ctx.String(config.ReplayTxFlag.Name))
+			txHash = &hash
+		} else {
+			block = new(big.Int).SetUint64(ctx.Uint64(config.ReplayBlockFlag.Name))
+		}
+		err = ethereum.Replay(chainConfigs[src], logger, c, block, txHash)
+	case "substrate":
+		if hasTx {
+			return errors.New("--tx is not supported for substrate chains, use --block")
+		}
+		err = substrate.Replay(chainConfigs[src], logger, c, ctx.Uint64(config.ReplayBlockFlag.Name))
+	default:
+		return errors.New("unrecognized Chain Type")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(c.msgs) == 0 {
+		fmt.Println("No deposits found")
+		return nil
+	}
+	// Quarantined transfers may only be routed by releasing them
+	q, err := limits.NewQuarantine(chainConfigs[src].BlockstorePath, src, chainConfigs[src].From)
+	if err != nil {
+		return err
+	}
+	for _, m := range c.msgs {
+		fmt.Printf("%s deposit from chain %d to chain %d, nonce %d, resource %s\n", m.Type, m.Source, m.Destination, m.DepositNonce, m.ResourceId.Hex())
+		if quarantined(q, m) {
+			fmt.Println("  quarantined, release it through the admin API instead")
+		}
+	}
+	if ctx.Bool(config.DryRunFlag.Name) {
+		return nil
+	}
+
+	p, err := loadPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Fatal errors are reported by the writers as they occur, nothing is left to shut down
+	sysErr := make(chan error)
+	go func() {
+		for err := range sysErr {
+			log.Error("Writer reported a fatal error", "err", err)
+		}
+	}()
+
+	writers := make(map[msg.ChainId]resolver)
+	defer func() {
+		for _, w := range writers {
+			w.Stop()
+		}
+	}()
+	writer := func(dst msg.ChainId) (resolver, error) {
+		if w, ok := writers[dst]; ok {
+			return w, nil
+		}
+		dstChain, ok := chains[dst]
+		if !ok {
+			log.Error("Destination chain is not in the config", "dst", dst)
+			return nil, nil
+		}
+		newChain, err := initializeChain(dstChain.Type, chainConfigs[dst], log.Root().New("chain", dstChain.Name), sysErr, nil)
+		if err != nil {
+			return nil, err
+		}
+		if pc, ok := newChain.(policed); ok {
+			pc.SetPolicy(p)
+		}
+		// The queue belongs to the running relayer, writing it here would overwrite its entries
+		if qc, ok := newChain.(queued); ok {
+			qc.DisableMessageQueue()
+		}
+		writers[dst] = newChain.(resolver)
+		return writers[dst], nil
+	}
+	failed, err := submitReplayed(c.msgs, q, writer)
+	if err != nil {
+		return err
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d messages were not submitted", failed, len(c.msgs))
+	}
+	return nil
+}
+
+// replayByTx checks that exactly one of --block or --tx is given, and returns true if it is --tx
+func replayByTx(ctx *cli.Context) (bool, error) {
+	hasBlock, hasTx := ctx.IsSet(config.ReplayBlockFlag.Name), ctx.IsSet(config.ReplayTxFlag.Name)
+	if hasBlock == hasTx {
+		return false, errors.New("exactly one of --block or --tx must be given")
+	}
+	return hasTx, nil
+}
+
+// quarantined returns true if m is held in q. Quarantined transfers may only be routed by releasing them.
+func quarantined(q *limits.Quarantine, m msg.Message) bool {
+	_, ok := q.Find(m.Destination, m.DepositNonce)
+	return ok
+}
+
+// submitReplayed resolves each message that is not quarantined with the resolver writer returns for its destination,
+// which is nil if the destination is not configured. Returns the number of messages that were not submitted.
+func submitReplayed(msgs []msg.Message, q *limits.Quarantine, writer func(dst msg.ChainId) (resolver, error)) (int, error) {
+	failed := 0
+	for _, m := range msgs {
+		if quarantined(q, m) {
+			fmt.Printf("Refusing to submit quarantined nonce %d to chain %d\n", m.DepositNonce, m.Destination)
+			failed++
+			continue
+		}
+		w, err := writer(m.Destination)
+		if err != nil {
+			return failed, err
+		}
+		if w == nil {
+			failed++
+			continue
+		}
+
+		// Executing a proposal that passes with this vote is left to the relayers watching it, replay does not wait
+		if w.ResolveMessage(m) {
+			fmt.Printf("Submitted nonce %d to chain %d, the vote was sent but execution is not awaited\n", m.DepositNonce, m.Destination)
+		} else {
+			fmt.Printf("Failed to submit nonce %d to chain %d\n", m.DepositNonce, m.Destination)
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// queued is implemented by chains whose writer records messages in a queue
+type queued interface {
+	DisableMessageQueue()
+}
+
+// resolver is implemented by chains that can resolve a message without being started
+type resolver interface {
+	core.Chain
+	ResolveMessage(m msg.Message) bool
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/core"
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/config"
+)
+
+func TestReplayByTx(t *testing.T) {
+	testCases := []struct {
+		flags  []string
+		values []interface{}
+		hasTx  bool
+		err    bool
+	}{
+		{flags: []string{config.ReplayBlockFlag.Name}, values: []interface{}{uint(10)}},
+		{flags: []string{config.ReplayTxFlag.Name}, values: []interface{}{"0x01"}, hasTx: true},
+		{flags: []string{config.ReplayBlockFlag.Name, config.ReplayTxFlag.Name}, values: []interface{}{uint(10), "0x01"}, err: true},
+		{err: true},
+	}
+
+	for i, tc := range testCases {
+		ctx, err := newTestContext("replay", tc.flags, tc.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hasTx, err := replayByTx(ctx)
+		if tc.err {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %s", i, err)
+		} else if hasTx != tc.hasTx {
+			t.Errorf("case %d: expected hasTx %t, got %t", i, tc.hasTx, hasTx)
+		}
+	}
+}
+
+// testResolver records the messages it resolves
+type testResolver struct {
+	core.Chain
+	resolved []msg.Message
+}
+
+func (r *testResolver) ResolveMessage(m msg.Message) bool {
+	r.resolved = append(r.resolved, m)
+	return true
+}
+
+func TestSubmitReplayedRefusesQuarantined(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	q, err := limits.NewQuarantine(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := msg.NewFungibleTransfer(1, 2, 1, big.NewInt(100), msg.ResourceId{}, []byte{})
+	released := msg.NewFungibleTransfer(1, 2, 2, big.NewInt(100), msg.ResourceId{}, []byte{})
+	if err := q.Add(held, "exceeds limit"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &testResolver{}
+	failed, err := submitReplayed([]msg.Message{held, released}, q, func(dst msg.ChainId) (resolver, error) { return r, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 1 {
+		t.Fatalf("expected 1 message not submitted, got %d", failed)
+	}
+	if len(r.resolved) != 1 || r.resolved[0].DepositNonce != released.DepositNonce {
+		t.Fatalf("expected only nonce %d to be submitted, got %v", released.DepositNonce, r.resolved)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"fmt"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge/chains/ethereum"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"github.com/UltronFoundationDev/chainbridge/chains/substrate"
+	"github.com/UltronFoundationDev/chainbridge/config"
+	"github.com/urfave/cli/v2"
+)
+
+var configCommand = cli.Command{
+	Name:  "config",
+	Usage: "manage bridge configuration",
+	Description: "The config command is used to work with the bridge configuration.\n" +
+		"\tTo check every chain of a config: chainbridge config validate --config path/to/config",
+	Subcommands: []*cli.Command{
+		{
+			Action: handleValidateCmd,
+			Name:   "validate",
+			Usage:  "validate the configuration without starting the relayer",
+			Flags:  []cli.Flag{config.ConfigFileFlag, config.VerbosityFlag, config.OfflineFlag},
+			Description: "The validate subcommand checks the options of every chain, connects to each endpoint and\n" +
+				"\tverifies the contracts and chain IDs. Use --offline to only check the options.",
+		},
+	},
+}
+
+// handleValidateCmd checks every chain of the config and prints a report, returning an error if any chain failed
+func handleValidateCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.ReadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	offline := ctx.Bool(config.OfflineFlag.Name)
+	if offline {
+		fmt.Println("Skipping network checks (--offline)")
+	}
+
+	failed := 0
+	for _, chain := range cfg.Chains {
+		err := validateChain(ctx, chain, offline)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL  chain %q (type: %s, id: %s): %s\n", chain.Name, chain.Type, chain.Id, err)
+		} else {
+			fmt.Printf("OK    chain %q (type: %s, id: %s)\n", chain.Name, chain.Type, chain.Id)
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d chains failed validation", failed, len(cfg.Chains))
+	}
+	// Decimals can only be checked once the decimals of every chain are known
+	err = cfg.ValidateDecimals()
+	if err != nil {
+		fmt.Printf("FAIL  decimals: %s\n", err)
+		return err
+	}
+	fmt.Printf("All %d chains are valid\n", len(cfg.Chains))
+	return nil
+}
+
+func validateChain(ctx *cli.Context, chain config.RawChainConfig, offline bool) error {
+	err := chain.Validate()
+	if err != nil {
+		return err
+	}
+	_, err = limits.Parse(chain.Limits)
+	if err != nil {
+		return err
+	}
+
+	chainConfig, err := newChainConfig(ctx, chain, "", false)
+	if err != nil {
+		return fmt.Errorf("invalid chain id: %w", err)
+	}
+
+	logger := log.Root().New("chain", chainConfig.Name)
+	switch chain.Type {
+	case "ethereum":
+		return ethereum.ValidateConfig(chainConfig, logger, offline)
+	case "substrate":
+		return substrate.ValidateConfig(chainConfig, logger, offline)
+	default:
+		return fmt.Errorf("unrecognized Chain Type %q", chain.Type)
+	}
+}
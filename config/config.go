@@ -7,11 +7,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 const DefaultConfigPath = "/config/config.json"
@@ -20,19 +27,20 @@ const DefaultBlockStorePath = "./blockstore"
 const DefaultBlockTimeout = int64(180) // 3 minutes
 
 type Config struct {
-	Chains       []RawChainConfig `json:"chains"`
-	KeystorePath string           `json:"keystorePath,omitempty"`
+	Chains       []RawChainConfig `json:"chains" yaml:"chains"`
+	KeystorePath string           `json:"keystorePath,omitempty" yaml:"keystorePath,omitempty"`
 }
 
 // RawChainConfig is parsed directly from the config file and should be using to construct the core.ChainConfig
 type RawChainConfig struct {
-	Name     string                              `json:"name"`
-	Type     string                              `json:"type"`
-	Id       string                              `json:"id"`       // ChainID
-	Endpoint string                              `json:"endpoint"` // url for rpc endpoint
-	From     string                              `json:"from"`     // address of key to use
-	Opts     map[string]string                   `json:"opts"`
-	Decimals map[msg.ChainId]map[string][2]uint8 `json:"decimals"`
+	Name     string                              `json:"name" yaml:"name"`
+	Type     string                              `json:"type" yaml:"type"`
+	Id       string                              `json:"id" yaml:"id"`             // ChainID
+	Endpoint string                              `json:"endpoint" yaml:"endpoint"` // url for rpc endpoint
+	From     string                              `json:"from" yaml:"from"`         // address of key to use
+	Opts     map[string]string                   `json:"opts" yaml:"opts"`
+	Decimals map[msg.ChainId]map[string][2]uint8 `json:"decimals" yaml:"decimals"`
+	Limits   map[string]limits.Config            `json:"limits" yaml:"limits"` // Transfer limits by resource ID
 }
 
 func NewConfig() *Config {
@@ -70,44 +78,105 @@ func (c *Config) ToJSON(file string) *os.File {
 
 func (c *Config) validate() error {
 	for _, chain := range c.Chains {
-		if chain.Type == "" {
-			return fmt.Errorf("required field chain.Type empty for chain %s", chain.Id)
-		}
-		if chain.Endpoint == "" {
-			return fmt.Errorf("required field chain.Endpoint empty for chain %s", chain.Id)
+		err := chain.Validate()
+		if err != nil {
+			return err
 		}
-		if chain.Name == "" {
-			return fmt.Errorf("required field chain.Name empty for chain %s", chain.Id)
+	}
+	return c.ValidateDecimals()
+}
+
+// ValidateDecimals checks that no resource has decimals for the same pair of chains on both chains when one of them
+// is a substrate chain. A substrate chain converts amounts in both directions with its own decimals, so decimals on
+// the other chain would convert the amounts twice.
+func (c *Config) ValidateDecimals() error {
+	chains := make(map[msg.ChainId]*RawChainConfig)
+	for i := range c.Chains {
+		id, err := strconv.ParseUint(c.Chains[i].Id, 10, 8)
+		if err != nil {
+			continue
 		}
-		if chain.Id == "" {
-			return fmt.Errorf("required field chain.Id empty for chain %s", chain.Id)
+		chains[msg.ChainId(id)] = &c.Chains[i]
+	}
+
+	for id, chain := range chains {
+		if chain.Type != "substrate" {
+			continue
 		}
-		if chain.From == "" {
-			return fmt.Errorf("required field chain.From empty for chain %s", chain.Id)
+		for dst, resources := range chain.Decimals {
+			other, ok := chains[dst]
+			if !ok {
+				continue
+			}
+			for rId := range resources {
+				for otherRId := range other.Decimals[id] {
+					if normalizeResourceId(rId) == normalizeResourceId(otherRId) {
+						return fmt.Errorf("decimals of resource %s are set on both chain %s and substrate chain %s, which converts amounts in both directions, remove them from chain %s", rId, other.Name, chain.Name, other.Name)
+					}
+				}
+			}
 		}
 	}
 	return nil
 }
 
+func normalizeResourceId(rId string) string {
+	return strings.ToLower(strings.TrimPrefix(rId, "0x"))
+}
+
+// Validate checks the required fields of the chain are set
+func (chain *RawChainConfig) Validate() error {
+	if chain.Type == "" {
+		return fmt.Errorf("required field chain.Type empty for chain %s", chain.Id)
+	}
+	if chain.Endpoint == "" {
+		return fmt.Errorf("required field chain.Endpoint empty for chain %s", chain.Id)
+	}
+	if chain.Name == "" {
+		return fmt.Errorf("required field chain.Name empty for chain %s", chain.Id)
+	}
+	if chain.Id == "" {
+		return fmt.Errorf("required field chain.Id empty for chain %s", chain.Id)
+	}
+	if chain.From == "" {
+		return fmt.Errorf("required field chain.From empty for chain %s", chain.Id)
+	}
+	return nil
+}
+
 func GetConfig(ctx *cli.Context) (*Config, error) {
-	var fig Config
-	path := DefaultConfigPath
+	fig, err := ReadConfig(ctx)
+	if err != nil {
+		return fig, err
+	}
+	err = fig.validate()
+	if err != nil {
+		return nil, err
+	}
+	return fig, nil
+}
+
+// Path returns the config file given by the cli context, or the default path
+func Path(ctx *cli.Context) string {
 	if file := ctx.String(ConfigFileFlag.Name); file != "" {
-		path = file
+		return file
 	}
+	return DefaultConfigPath
+}
+
+// ReadConfig loads the config file given by the cli context without validating the chains
+func ReadConfig(ctx *cli.Context) (*Config, error) {
+	var fig Config
+	path := Path(ctx)
 	err := loadConfig(path, &fig)
 	if err != nil {
-		log.Warn("err loading json file", "err", err.Error())
+		log.Warn("err loading config file", "err", err.Error())
 		return &fig, err
 	}
 	if ksPath := ctx.String(KeystorePathFlag.Name); ksPath != "" {
 		fig.KeystorePath = ksPath
 	}
 	log.Debug("Loaded config", "path", path)
-	err = fig.validate()
-	if err != nil {
-		return nil, err
-	}
 	return &fig, nil
 }
 
@@ -124,14 +193,144 @@ func loadConfig(file string, config *Config) error {
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
-	if ext == ".json" {
-		if err = json.NewDecoder(f).Decode(&config); err != nil {
+	switch ext {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&config)
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(f).Decode(config)
+	case ".toml":
+		err = decodeTOML(f, config)
+	default:
+		return fmt.Errorf("unrecognized extention: %s", ext)
+	}
+	if err != nil {
+		return err
+	}
+
+	return config.interpolate()
+}
+
+// tomlConfig mirrors Config, as toml tables only have string keys the decimals are keyed by the chain ID string
+type tomlConfig struct {
+	Chains []struct {
+		Name     string                         `toml:"name"`
+		Type     string                         `toml:"type"`
+		Id       string                         `toml:"id"`
+		Endpoint string                         `toml:"endpoint"`
+		From     string                         `toml:"from"`
+		Opts     map[string]string              `toml:"opts"`
+		Decimals map[string]map[string][2]uint8 `toml:"decimals"`
+		Limits   map[string]limits.Config       `toml:"limits"`
+	} `toml:"chains"`
+	KeystorePath string `toml:"keystorePath"`
+}
+
+func decodeTOML(r io.Reader, config *Config) error {
+	var raw tomlConfig
+	_, err := toml.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	config.KeystorePath = raw.KeystorePath
+	for _, c := range raw.Chains {
+		chain := RawChainConfig{
+			Name:     c.Name,
+			Type:     c.Type,
+			Id:       c.Id,
+			Endpoint: c.Endpoint,
+			From:     c.From,
+			Opts:     c.Opts,
+			Limits:   c.Limits,
+		}
+		if c.Decimals != nil {
+			chain.Decimals = make(map[msg.ChainId]map[string][2]uint8)
+			for id, decimals := range c.Decimals {
+				n, err := strconv.ParseUint(id, 10, 8)
+				if err != nil {
+					return fmt.Errorf("invalid chain ID %q in decimals of chain %s", id, c.Name)
+				}
+				chain.Decimals[msg.ChainId(n)] = decimals
+			}
+		}
+		config.Chains = append(config.Chains, chain)
+	}
+	return nil
+}
+
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces every ${VAR} in s with the value of the environment variable VAR, which must be set
+func expandEnv(s string) (string, error) {
+	var err error
+	res := envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := envVarPattern.FindStringSubmatch(match)[1]
+		val, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable %s is not set", name)
+		}
+		return val
+	})
+	return res, err
+}
+
+// interpolate expands environment variables in all string fields of the config
+func (c *Config) interpolate() error {
+	fields := []*string{&c.KeystorePath}
+	for i := range c.Chains {
+		chain := &c.Chains[i]
+		fields = append(fields, &chain.Name, &chain.Type, &chain.Id, &chain.Endpoint, &chain.From)
+	}
+
+	for _, f := range fields {
+		val, err := expandEnv(*f)
+		if err != nil {
 			return err
 		}
-	} else {
-		return fmt.Errorf("unrecognized extention: %s", ext)
+		*f = val
 	}
 
+	for i := range c.Chains {
+		chain := &c.Chains[i]
+		for k, v := range chain.Opts {
+			val, err := expandEnv(v)
+			if err != nil {
+				return fmt.Errorf("opts.%s of chain %s: %w", k, chain.Name, err)
+			}
+			chain.Opts[k] = val
+		}
+
+		for dst, resources := range chain.Decimals {
+			expanded := make(map[string][2]uint8, len(resources))
+			for k, v := range resources {
+				key, err := expandEnv(k)
+				if err != nil {
+					return fmt.Errorf("decimals.%d of chain %s: %w", dst, chain.Name, err)
+				}
+				expanded[key] = v
+			}
+			chain.Decimals[dst] = expanded
+		}
+
+		if chain.Limits != nil {
+			expanded := make(map[string]limits.Config, len(chain.Limits))
+			for k, l := range chain.Limits {
+				key, err := expandEnv(k)
+				if err != nil {
+					return fmt.Errorf("limits of chain %s: %w", chain.Name, err)
+				}
+				for _, f := range []*string{&l.MaxAmount, &l.WindowAmount, &l.RecipientAmount, &l.Window} {
+					*f, err = expandEnv(*f)
+					if err != nil {
+						return fmt.Errorf("limits.%s of chain %s: %w", key, chain.Name, err)
+					}
+				}
+				expanded[key] = l
+			}
+			chain.Limits = expanded
+		}
+	}
 	return nil
 }
@@ -9,8 +9,11 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/UltronFoundationDev/chainbridge-utils/msg"
+	"github.com/UltronFoundationDev/chainbridge/chains/limits"
 	"github.com/urfave/cli/v2"
 )
 
@@ -148,3 +151,140 @@ func TestValdiateConfig(t *testing.T) {
 		t.Fatal("must require name field")
 	}
 }
+
+func TestValidateDecimals(t *testing.T) {
+	rId := "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+	eth := RawChainConfig{Name: "eth", Type: "ethereum", Id: "1", Endpoint: "endpoint", From: "0x0"}
+	sub := RawChainConfig{Name: "sub", Type: "substrate", Id: "2", Endpoint: "endpoint", From: "0x0"}
+	sub.Decimals = map[msg.ChainId]map[string][2]uint8{1: {rId: {12, 18}}}
+
+	// Decimals on the substrate chain alone cover both directions
+	cfg := Config{Chains: []RawChainConfig{eth, sub}}
+	err := cfg.validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other resources may still be converted by the ethereum chain
+	eth.Decimals = map[msg.ChainId]map[string][2]uint8{2: {"0x01": {18, 12}}}
+	cfg = Config{Chains: []RawChainConfig{eth, sub}}
+	err = cfg.validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth.Decimals = map[msg.ChainId]map[string][2]uint8{2: {strings.ToUpper(rId[2:]): {18, 12}}}
+	cfg = Config{Chains: []RawChainConfig{eth, sub}}
+	err = cfg.validate()
+	if err == nil || !strings.Contains(err.Error(), "remove them from chain eth") {
+		t.Fatalf("expected error for decimals set on both chains, got %v", err)
+	}
+}
+
+func writeTempConfig(t *testing.T, pattern, contents string) string {
+	f, err := ioutil.TempFile(os.TempDir(), pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestLoadYAMLAndTOMLConfig(t *testing.T) {
+	os.Setenv("CHAINBRIDGE_TEST_ENDPOINT", "ws://node:8545")
+	os.Setenv("CHAINBRIDGE_TEST_API_KEY", "secret")
+	os.Setenv("CHAINBRIDGE_TEST_RESOURCE", "0x01")
+	os.Setenv("CHAINBRIDGE_TEST_MAX_AMOUNT", "100")
+	defer os.Unsetenv("CHAINBRIDGE_TEST_ENDPOINT")
+	defer os.Unsetenv("CHAINBRIDGE_TEST_API_KEY")
+	defer os.Unsetenv("CHAINBRIDGE_TEST_RESOURCE")
+	defer os.Unsetenv("CHAINBRIDGE_TEST_MAX_AMOUNT")
+
+	expected := &Config{
+		Chains: []RawChainConfig{{
+			Name:     "chain",
+			Type:     "ethereum",
+			Id:       "1",
+			Endpoint: "ws://node:8545",
+			From:     "0x0",
+			Opts:     map[string]string{"http": "true", "egsApiKey": "secret"},
+			Decimals: map[msg.ChainId]map[string][2]uint8{2: {"0x01": {18, 6}}},
+			Limits:   map[string]limits.Config{"0x01": {MaxAmount: "100", Window: "1h"}},
+		}},
+		KeystorePath: "./keys",
+	}
+
+	yamlFile := writeTempConfig(t, "*.yaml", `
+keystorePath: ./keys
+chains:
+  - name: chain
+    type: ethereum
+    id: "1"
+    endpoint: ${CHAINBRIDGE_TEST_ENDPOINT}
+    from: "0x0"
+    opts:
+      http: true
+      egsApiKey: ${CHAINBRIDGE_TEST_API_KEY}
+    decimals:
+      2:
+        "${CHAINBRIDGE_TEST_RESOURCE}": [18, 6]
+    limits:
+      "${CHAINBRIDGE_TEST_RESOURCE}":
+        maxAmount: ${CHAINBRIDGE_TEST_MAX_AMOUNT}
+        window: 1h
+`)
+	defer os.Remove(yamlFile)
+
+	tomlFile := writeTempConfig(t, "*.toml", `
+keystorePath = "./keys"
+
+[[chains]]
+name = "chain"
+type = "ethereum"
+id = "1"
+endpoint = "${CHAINBRIDGE_TEST_ENDPOINT}"
+from = "0x0"
+
+[chains.opts]
+http = "true"
+egsApiKey = "${CHAINBRIDGE_TEST_API_KEY}"
+
+[chains.decimals.2]
+"${CHAINBRIDGE_TEST_RESOURCE}" = [18, 6]
+
+[chains.limits."${CHAINBRIDGE_TEST_RESOURCE}"]
+maxAmount = "${CHAINBRIDGE_TEST_MAX_AMOUNT}"
+window = "1h"
+`)
+	defer os.Remove(tomlFile)
+
+	for _, file := range []string{yamlFile, tomlFile} {
+		var cfg Config
+		err := loadConfig(file, &cfg)
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		if !reflect.DeepEqual(&cfg, expected) {
+			t.Errorf("%s did not match\ngot: %+v\nexpected: %+v", file, cfg.Chains[0], expected.Chains[0])
+		}
+	}
+}
+
+func TestInterpolateMissingEnvVar(t *testing.T) {
+	file := writeTempConfig(t, "*.yaml", `
+chains:
+  - name: chain
+    endpoint: ${CHAINBRIDGE_TEST_UNSET}
+`)
+	defer os.Remove(file)
+
+	var cfg Config
+	err := loadConfig(file, &cfg)
+	if err == nil {
+		t.Fatal("expected unset environment variable to fail")
+	}
+}
@@ -4,6 +4,8 @@
 package config
 
 import (
+	"time"
+
 	log "github.com/ChainSafe/log15"
 	"github.com/urfave/cli/v2"
 )
@@ -11,12 +13,13 @@ import (
 // Env vars
 var (
 	HealthBlockTimeout = "BLOCK_TIMEOUT"
+	AdminToken         = "ADMIN_TOKEN"
 )
 
 var (
 	ConfigFileFlag = &cli.StringFlag{
 		Name:  "config",
-		Usage: "JSON configuration file",
+		Usage: "JSON, YAML or TOML configuration file",
 	}
 
 	VerbosityFlag = &cli.StringFlag{
@@ -25,6 +28,11 @@ var (
 		Value: log.LvlInfo.String(),
 	}
 
+	JSONLogFlag = &cli.BoolFlag{
+		Name:  "jsonLog",
+		Usage: "Output logs as JSON objects separated by newlines",
+	}
+
 	KeystorePathFlag = &cli.StringFlag{
 		Name:  "keystore",
 		Usage: "Path to keystore directory",
@@ -46,6 +54,17 @@ var (
 		Name:  "latest",
 		Usage: "Overrides blockstore and start block, starts from latest block",
 	}
+
+	ShutdownTimeoutFlag = &cli.DurationFlag{
+		Name:  "shutdownTimeout",
+		Usage: "Time to wait for pending votes and executions to complete when shutting down",
+		Value: time.Second * 30,
+	}
+
+	PolicyFileFlag = &cli.StringFlag{
+		Name:  "policy",
+		Usage: "JSON, YAML or TOML file of denied recipients, resources and source chains, reloaded when modified",
+	}
 )
 
 // Metrics flags
@@ -62,6 +81,20 @@ var (
 	}
 )
 
+// Admin API flags
+var (
+	AdminFlag = &cli.BoolFlag{
+		Name:  "admin",
+		Usage: "Enables the admin API on localhost, requests must carry the token set in ADMIN_TOKEN",
+	}
+
+	AdminPortFlag = &cli.IntFlag{
+		Name:  "adminPort",
+		Usage: "Port to serve the admin API on",
+		Value: 8002,
+	}
+)
+
 // Generate subcommand flags
 var (
 	PasswordFlag = &cli.StringFlag{
@@ -94,6 +127,35 @@ var (
 	}
 )
 
+// Config subcommand flags
+var (
+	OfflineFlag = &cli.BoolFlag{
+		Name:  "offline",
+		Usage: "Skip checks that connect to the chains",
+	}
+)
+
+// Replay subcommand flags
+var (
+	ReplayChainFlag = &cli.UintFlag{
+		Name:     "chain",
+		Usage:    "ID of the chain the deposit was made on",
+		Required: true,
+	}
+	ReplayBlockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Block to replay the deposits of",
+	}
+	ReplayTxFlag = &cli.StringFlag{
+		Name:  "tx",
+		Usage: "Hash of the transaction to replay the deposits of (ethereum only)",
+	}
+	DryRunFlag = &cli.BoolFlag{
+		Name:  "dryRun",
+		Usage: "Only print the messages that would be submitted",
+	}
+)
+
 // Test Setting Flags
 var (
 	TestKeyFlag = &cli.StringFlag{
@@ -0,0 +1,163 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var _ bind.ContractBackend = &backend{}
+var _ oracle.NodeBackend = &backend{}
+
+// backend is a bind.ContractBackend that sends every request to the healthiest endpoint of a connection
+type backend struct {
+	c *Connection
+}
+
+func (b *backend) CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) (res []byte, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (res []byte, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) HeaderByNumber(ctx context.Context, number *big.Int) (res *types.Header, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) PendingCodeAt(ctx context.Context, account ethcommon.Address) (res []byte, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) PendingNonceAt(ctx context.Context, account ethcommon.Address) (res uint64, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) SuggestGasPrice(ctx context.Context) (res *big.Int, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) SuggestGasTipCap(ctx context.Context) (res *big.Int, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (res uint64, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.EstimateGas(ctx, call)
+		return err
+	})
+	return res, err
+}
+
+// SendTransaction submits the transaction to the healthiest endpoint. If a failing endpoint did receive the
+// transaction before it is retried elsewhere, the "already known" response is treated as success.
+func (b *backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	attempts := 0
+	return b.c.call(func(client *ethclient.Client) error {
+		attempts++
+		err := client.SendTransaction(ctx, tx)
+		if err != nil && attempts > 1 && strings.Contains(err.Error(), "already known") {
+			return nil
+		}
+		return err
+	})
+}
+
+func (b *backend) TransactionReceipt(ctx context.Context, hash ethcommon.Hash) (res *types.Receipt, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.TransactionReceipt(ctx, hash)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (res []types.Log, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return res, err
+}
+
+func (b *backend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (res ethereum.Subscription, err error) {
+	err = b.c.call(func(client *ethclient.Client) error {
+		res, err = client.SubscribeFilterLogs(ctx, query, ch)
+		return err
+	})
+	return res, err
+}
+
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the fee history of the latest blocks at the given reward percentiles
+func (b *backend) FeeHistory(ctx context.Context, blocks uint64, percentiles []float64) (*oracle.FeeHistory, error) {
+	var res feeHistoryResult
+	err := b.c.callEndpoint(func(e *endpoint) error {
+		return e.getRPC().CallContext(ctx, &res, "eth_feeHistory", hexutil.Uint64(blocks), "latest", percentiles)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &oracle.FeeHistory{
+		OldestBlock:  (*big.Int)(res.OldestBlock),
+		Reward:       make([][]*big.Int, len(res.Reward)),
+		BaseFee:      make([]*big.Int, len(res.BaseFee)),
+		GasUsedRatio: res.GasUsedRatio,
+	}
+	for i, rewards := range res.Reward {
+		history.Reward[i] = make([]*big.Int, len(rewards))
+		for j, r := range rewards {
+			history.Reward[i][j] = (*big.Int)(r)
+		}
+	}
+	for i, f := range res.BaseFee {
+		history.BaseFee[i] = (*big.Int)(f)
+	}
+	return history, nil
+}
@@ -8,73 +8,118 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ChainSafe/log15"
 	"github.com/UltronFoundationDev/chainbridge-utils/crypto/secp256k1"
-	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/egs"
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var BlockRetryInterval = time.Second * 5
 
 type Connection struct {
-	endpoint      string
+	endpoints     []*endpoint
+	chainId       *big.Int  // Chain ID every endpoint must serve, set by Connect
+	current       *endpoint // Endpoint that served the most recent request
 	http          bool
 	kp            *secp256k1.Keypair
+	signer        Signer
 	gasLimit      *big.Int
 	maxGasPrice   *big.Int
 	minGasPrice   *big.Int
 	gasMultiplier *big.Float
-	egsApiKey     string
-	egsSpeed      string
-	conn          *ethclient.Client
+	gasMargin     uint64 // Percentage added to gas estimates
+	gasOracle     oracle.GasPriceOracle
+	backend       *backend
+	fees          feeBackend // Source of fee history and tip suggestions, the backend unless testing
+	// Priority fee strategy, see SetTipStrategy
+	tipPercentile    float64
+	feeHistoryBlocks uint64
+	minTip           *big.Int
+	maxTip           *big.Int
 	// signer    ethtypes.Signer
-	opts     *bind.TransactOpts
-	callOpts *bind.CallOpts
-	nonce    uint64
-	optsLock sync.Mutex
-	log      log15.Logger
-	stop     chan int // All routines should exit when this channel is closed
+	opts        *bind.TransactOpts
+	callOpts    *bind.CallOpts
+	nonce       uint64
+	optsLock    sync.Mutex
+	currentLock sync.Mutex
+	log         log15.Logger
+	stop        chan int // All routines should exit when this channel is closed
 }
 
 // NewConnection returns an uninitialized connection, must call Connection.Connect() before using.
-func NewConnection(endpoint string, http bool, kp *secp256k1.Keypair, log log15.Logger, gasLimit, maxGasPrice, minGasPrice *big.Int, gasMultiplier *big.Float, gsnApiKey, gsnSpeed string) *Connection {
-	return &Connection{
-		endpoint:      endpoint,
+func NewConnection(url string, http bool, kp *secp256k1.Keypair, log log15.Logger, gasLimit, maxGasPrice, minGasPrice *big.Int, gasMultiplier *big.Float) *Connection {
+	c := &Connection{
+		endpoints:     []*endpoint{{url: url}},
 		http:          http,
 		kp:            kp,
 		gasLimit:      gasLimit,
 		maxGasPrice:   maxGasPrice,
 		minGasPrice:   minGasPrice,
 		gasMultiplier: gasMultiplier,
-		egsApiKey:     gsnApiKey,
-		egsSpeed:      gsnSpeed,
+		gasMargin:     DefaultGasMargin,
 		log:           log,
 		stop:          make(chan int),
 	}
+	c.backend = &backend{c: c}
+	c.gasOracle = oracle.NewNode(c.backend, oracle.DefaultFeeHistoryBlocks, 0)
+	c.fees = c.backend
+	c.feeHistoryBlocks = oracle.DefaultFeeHistoryBlocks
+	if kp != nil {
+		c.signer = NewKeystoreSigner(kp)
+	}
+	return c
+}
+
+// SetSigner replaces the keystore signer, eg. with a RemoteSigner. Must be called before Connection.Connect().
+func (c *Connection) SetSigner(s Signer) {
+	c.signer = s
 }
 
-// Connect starts the ethereum WS connection
+// SetGasPriceOracle replaces the default node oracle used to suggest legacy gas prices
+func (c *Connection) SetGasPriceOracle(o oracle.GasPriceOracle) {
+	c.gasOracle = o
+}
+
+// NodeBackend returns a backend for node gas price oracles that fails over between the connection's endpoints
+func (c *Connection) NodeBackend() oracle.NodeBackend {
+	return c.backend
+}
+
+// AddEndpoints adds further nodes for the connection to fail over to. Must be called before Connection.Connect().
+func (c *Connection) AddEndpoints(urls ...string) {
+	for _, url := range urls {
+		c.endpoints = append(c.endpoints, &endpoint{url: url})
+	}
+}
+
+// Connect starts the ethereum WS connection to every endpoint. Endpoints that cannot be reached or do not answer are
+// retried by the health check, but at least one must answer and all answering endpoints must serve the same chain.
 func (c *Connection) Connect() error {
-	c.log.Info("Connecting to ethereum chain...", "url", c.endpoint)
-	var rpcClient *rpc.Client
-	var err error
-	// Start http or ws client
-	if c.http {
-		rpcClient, err = rpc.DialHTTP(c.endpoint)
-	} else {
-		rpcClient, err = rpc.DialContext(context.Background(), c.endpoint)
+	var dialErr error
+	for _, e := range c.endpoints {
+		c.log.Info("Connecting to ethereum chain...", "url", e.url)
+		id, err := c.connectEndpoint(e)
+		if err != nil {
+			c.log.Error("Failed to connect to endpoint", "url", e.url, "err", err)
+			dialErr = err
+			continue
+		}
+		if c.chainId == nil {
+			c.chainId = id
+		} else if c.chainId.Cmp(id) != 0 {
+			return fmt.Errorf("endpoint %s serves chain %s, expected %s", e.url, id, c.chainId)
+		}
 	}
-	if err != nil {
-		return err
+	if c.chainId == nil {
+		return dialErr
 	}
-	c.conn = ethclient.NewClient(rpcClient)
 
 	// Construct tx opts, call opts, and nonce mechanism
 	opts, _, err := c.newTransactOpts(big.NewInt(0), c.gasLimit, c.maxGasPrice)
@@ -83,30 +128,53 @@ func (c *Connection) Connect() error {
 	}
 	c.opts = opts
 	c.nonce = 0
-	c.callOpts = &bind.CallOpts{From: c.kp.CommonAddress()}
+	c.callOpts = &bind.CallOpts{From: c.signer.Address()}
+
+	go c.monitorEndpoints()
 	return nil
 }
 
-// newTransactOpts builds the TransactOpts for the connection's keypair.
-func (c *Connection) newTransactOpts(value, gasLimit, gasPrice *big.Int) (*bind.TransactOpts, uint64, error) {
-	privateKey := c.kp.PrivateKey()
-	address := ethcrypto.PubkeyToAddress(privateKey.PublicKey)
-
-	nonce, err := c.conn.PendingNonceAt(context.Background(), address)
+// connectEndpoint dials e and returns the chain ID it serves. The endpoint is disconnected again if it does not answer.
+func (c *Connection) connectEndpoint(e *endpoint) (*big.Int, error) {
+	err := e.dial(c.http)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	id, err := e.getClient().ChainID(context.Background())
+	if err != nil {
+		e.disconnect()
+		return nil, fmt.Errorf("unable to query chain ID: %w", err)
 	}
+	return id, nil
+}
 
-	id, err := c.conn.ChainID(context.Background())
+// newTransactOpts builds the TransactOpts for the connection's signer.
+func (c *Connection) newTransactOpts(value, gasLimit, gasPrice *big.Int) (*bind.TransactOpts, uint64, error) {
+	address := c.signer.Address()
+
+	nonce, err := c.backend.PendingNonceAt(context.Background(), address)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, id)
+	var id *big.Int
+	err = c.call(func(client *ethclient.Client) error {
+		id, err = client.ChainID(context.Background())
+		return err
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
+	auth := &bind.TransactOpts{
+		From: address,
+		Signer: func(from ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return c.signer.SignTx(tx, id)
+		},
+	}
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = value
 	auth.GasLimit = uint64(gasLimit.Int64())
@@ -116,12 +184,25 @@ func (c *Connection) newTransactOpts(value, gasLimit, gasPrice *big.Int) (*bind.
 	return auth, nonce, nil
 }
 
+// Keypair returns the keystore keypair of the connection, which is nil when signing with a remote signer
 func (c *Connection) Keypair() *secp256k1.Keypair {
 	return c.kp
 }
 
+// Client returns the client of the currently healthiest endpoint. Requests made with it do not fail over to other
+// endpoints, use Backend or the connection's methods instead where possible.
 func (c *Connection) Client() *ethclient.Client {
-	return c.conn
+	endpoints := c.healthiest()
+	if len(endpoints) == 0 {
+		return nil
+	}
+	c.setCurrent(endpoints[0])
+	return endpoints[0].getClient()
+}
+
+// Backend returns a contract backend that fails over between the connection's endpoints on every request
+func (c *Connection) Backend() bind.ContractBackend {
+	return c.backend
 }
 
 func (c *Connection) Opts() *bind.TransactOpts {
@@ -133,29 +214,11 @@ func (c *Connection) CallOpts() *bind.CallOpts {
 }
 
 func (c *Connection) SafeEstimateGas(ctx context.Context) (*big.Int, error) {
-
-	var suggestedGasPrice *big.Int
-
-	// First attempt to use EGS for the gas price if the api key is supplied
-	if c.egsApiKey != "" {
-		price, err := egs.FetchGasPrice(c.egsApiKey, c.egsSpeed)
-		if err != nil {
-			c.log.Error("Couldn't fetch gasPrice from GSN", "err", err)
-		} else {
-			suggestedGasPrice = price
-		}
-	}
-
-	// Fallback to the node rpc method for the gas price if GSN did not provide a price
-	if suggestedGasPrice == nil {
-		c.log.Debug("Fetching gasPrice from node")
-		nodePriceEstimate, err := c.conn.SuggestGasPrice(context.TODO())
-		if err != nil {
-			return nil, err
-		} else {
-			suggestedGasPrice = nodePriceEstimate
-		}
+	suggestedGasPrice, err := c.gasOracle.GasPrice(ctx)
+	if err != nil {
+		return nil, err
 	}
+	c.log.Debug("Fetched gasPrice", "oracle", c.gasOracle.Name(), "gasPrice", suggestedGasPrice)
 
 	gasPrice := multiplyGasPrice(suggestedGasPrice, c.gasMultiplier)
 
@@ -179,7 +242,7 @@ func (c *Connection) EstimateGasLondon(ctx context.Context, baseFee *big.Int) (*
 		return maxPriorityFeePerGas, maxFeePerGas, nil
 	}
 
-	maxPriorityFeePerGas, err := c.conn.SuggestGasTipCap(context.TODO())
+	maxPriorityFeePerGas, err := c.suggestGasTipCap(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -219,7 +282,7 @@ func multiplyGasPrice(gasEstimate *big.Int, gasMultiplier *big.Float) *big.Int {
 func (c *Connection) LockAndUpdateOpts() error {
 	c.optsLock.Lock()
 
-	head, err := c.conn.HeaderByNumber(context.TODO(), nil)
+	head, err := c.backend.HeaderByNumber(context.TODO(), nil)
 	if err != nil {
 		c.UnlockOpts()
 		return err
@@ -244,7 +307,7 @@ func (c *Connection) LockAndUpdateOpts() error {
 		c.opts.GasPrice = gasPrice
 	}
 
-	nonce, err := c.conn.PendingNonceAt(context.Background(), c.opts.From)
+	nonce, err := c.backend.PendingNonceAt(context.Background(), c.opts.From)
 	if err != nil {
 		c.optsLock.Unlock()
 		return err
@@ -253,22 +316,55 @@ func (c *Connection) LockAndUpdateOpts() error {
 	return nil
 }
 
+// SetGasPriceLimits updates the maximum gas price and the multiplier applied to gas price estimates. The opts lock is
+// held so transactions being prepared are not affected midway.
+func (c *Connection) SetGasPriceLimits(maxGasPrice *big.Int, gasMultiplier *big.Float) {
+	c.optsLock.Lock()
+	defer c.optsLock.Unlock()
+	c.maxGasPrice = maxGasPrice
+	c.gasMultiplier = gasMultiplier
+}
+
 func (c *Connection) UnlockOpts() {
 	c.optsLock.Unlock()
 }
 
+// Balance returns the native token balance of the signer account at the latest block
+func (c *Connection) Balance() (*big.Int, error) {
+	var balance *big.Int
+	err := c.call(func(client *ethclient.Client) error {
+		var err error
+		balance, err = client.BalanceAt(context.Background(), c.signer.Address(), nil)
+		return err
+	})
+	return balance, err
+}
+
 // LatestBlock returns the latest block from the current chain
 func (c *Connection) LatestBlock() (*big.Int, error) {
-	header, err := c.conn.HeaderByNumber(context.Background(), nil)
+	var header *types.Header
+	err := c.callEndpoint(func(e *endpoint) error {
+		var err error
+		header, err = e.getClient().HeaderByNumber(context.Background(), nil)
+		if err == nil {
+			e.setHeight(header.Number)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return header.Number, nil
 }
 
+// TransactionReceipt returns the receipt of the mined transaction with the given hash
+func (c *Connection) TransactionReceipt(ctx context.Context, hash ethcommon.Hash) (*types.Receipt, error) {
+	return c.backend.TransactionReceipt(ctx, hash)
+}
+
 // EnsureHasBytecode asserts if contract code exists at the specified address
 func (c *Connection) EnsureHasBytecode(addr ethcommon.Address) error {
-	code, err := c.conn.CodeAt(context.Background(), addr, nil)
+	code, err := c.backend.CodeAt(context.Background(), addr, nil)
 	if err != nil {
 		return err
 	}
@@ -309,8 +405,103 @@ func (c *Connection) WaitForBlock(targetBlock *big.Int, delay *big.Int) error {
 
 // Close terminates the client connection and stops any running routines
 func (c *Connection) Close() {
-	if c.conn != nil {
-		c.conn.Close()
+	for _, e := range c.endpoints {
+		if client := e.getClient(); client != nil {
+			client.Close()
+		}
 	}
 	close(c.stop)
 }
+
+// call runs fn against the healthiest endpoint, moving on to the next healthiest if the node fails to respond
+func (c *Connection) call(fn func(client *ethclient.Client) error) error {
+	return c.callEndpoint(func(e *endpoint) error {
+		return fn(e.getClient())
+	})
+}
+
+func (c *Connection) callEndpoint(fn func(e *endpoint) error) error {
+	endpoints := c.healthiest()
+	if len(endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
+	var err error
+	for _, e := range endpoints {
+		c.setCurrent(e)
+		start := time.Now()
+		err = fn(e)
+		e.record(time.Since(start), err)
+		if !isNodeFault(err) {
+			return err
+		}
+		c.log.Warn("Endpoint request failed", "url", e.url, "err", err)
+	}
+	return err
+}
+
+// healthiest returns the connected endpoints ordered from healthiest to least healthy
+func (c *Connection) healthiest() []*endpoint {
+	var highest *big.Int
+	res := make([]*endpoint, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		if !e.connected() {
+			continue
+		}
+		res = append(res, e)
+		if h := e.getHeight(); h != nil && (highest == nil || h.Cmp(highest) > 0) {
+			highest = h
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].score(highest) < res[j].score(highest)
+	})
+	return res
+}
+
+func (c *Connection) setCurrent(e *endpoint) {
+	c.currentLock.Lock()
+	defer c.currentLock.Unlock()
+	if c.current != e {
+		if c.current != nil {
+			c.log.Info("Switching endpoint", "from", c.current.url, "to", e.url)
+		}
+		c.current = e
+	}
+}
+
+// monitorEndpoints periodically queries the latest block of every endpoint to keep their health scores current,
+// and dials endpoints again that could not be reached or whose recent requests all failed.
+func (c *Connection) monitorEndpoints() {
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(EndpointHealthInterval):
+			for _, e := range c.endpoints {
+				if e.failing() {
+					id, err := c.connectEndpoint(e)
+					if err != nil {
+						c.log.Debug("Endpoint still unreachable", "url", e.url, "err", err)
+						continue
+					} else if id.Cmp(c.chainId) != 0 {
+						c.log.Error("Endpoint serves a different chain, not using it", "url", e.url, "chainId", id, "expected", c.chainId)
+						e.disconnect()
+						continue
+					}
+					c.log.Info("Reconnected to endpoint", "url", e.url)
+				}
+
+				start := time.Now()
+				header, err := e.getClient().HeaderByNumber(context.Background(), nil)
+				e.record(time.Since(start), err)
+				if err != nil {
+					c.log.Warn("Endpoint health check failed", "url", e.url, "err", err)
+					continue
+				}
+				e.setHeight(header.Number)
+			}
+		}
+	}
+}
@@ -24,7 +24,7 @@ var MinGasPrice = big.NewInt(ethutils.DefaultMinGasPrice)
 var GasMultipler = big.NewFloat(ethutils.DefaultGasMultiplier)
 
 func TestConnect(t *testing.T) {
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -41,7 +41,7 @@ func TestContractCode(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, MinGasPrice, GasMultipler)
 	err = conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -67,7 +67,7 @@ func TestConnection_SafeEstimateGas(t *testing.T) {
 	maxGasPrice := big.NewInt(0)
 	// MaxGasPrice is the constant price on the dev network, so we increase it here by 1 to ensure it adjusts
 	maxGasPrice.Add(MaxGasPrice, big.NewInt(1))
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -86,7 +86,7 @@ func TestConnection_SafeEstimateGas(t *testing.T) {
 
 func TestConnection_SafeEstimateGasMax(t *testing.T) {
 	maxPrice := big.NewInt(1)
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -107,7 +107,7 @@ func TestConnection_SafeEstimateGasMin(t *testing.T) {
 	minPrice := big.NewInt(1)
 	// When gasMultipler is zero, the gasPrice is zero if the effect of the minPrice is removed.
 	gasMultipler := big.NewFloat(0)
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, minPrice, gasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, MaxGasPrice, minPrice, gasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -130,7 +130,7 @@ func TestConnection_SafeEstimateGasSameMin(t *testing.T) {
 	minPrice := MaxGasPrice
 	maxGasPrice := big.NewInt(0)
 	maxGasPrice.Add(MaxGasPrice, big.NewInt(1))
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, minPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, minPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -151,14 +151,14 @@ func TestConnection_EstimateGasLondon(t *testing.T) {
 	// Set TestEndpoint to Goerli endpoint when testing as the current Github CI doesn't use the London version of geth
 	// Goerli commonly has a base fee of 7 wei with maxPriorityFeePerGas of 4.999999993 gwei
 	maxGasPrice := big.NewInt(100000000000)
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer conn.Close()
 
-	head, err := conn.conn.HeaderByNumber(context.Background(), nil)
+	head, err := conn.Client().HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -180,14 +180,14 @@ func TestConnection_EstimateGasLondonMax(t *testing.T) {
 	// Set TestEndpoint to Goerli endpoint when testing as the current Github CI doesn't use the London version of geth
 	// Goerli commonly has a base fee of 7 wei with maxPriorityFeePerGas of 4.999999993 gwei
 	maxGasPrice := big.NewInt(100)
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer conn.Close()
 
-	head, err := conn.conn.HeaderByNumber(context.Background(), nil)
+	head, err := conn.Client().HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -215,14 +215,14 @@ func TestConnection_EstimateGasLondonMin(t *testing.T) {
 	// Set TestEndpoint to Goerli endpoint when testing as the current Github CI doesn't use the London version of geth
 	// Goerli commonly has a base fee of 7 wei with maxPriorityFeePerGas of 4.999999993 gwei
 	maxGasPrice := big.NewInt(1)
-	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler, "", "")
+	conn := NewConnection(TestEndpoint, false, AliceKp, log15.Root(), GasLimit, maxGasPrice, MinGasPrice, GasMultipler)
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer conn.Close()
 
-	head, err := conn.conn.HeaderByNumber(context.Background(), nil)
+	head, err := conn.Client().HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
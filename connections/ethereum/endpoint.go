@@ -0,0 +1,158 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var EndpointHealthInterval = time.Second * 15
+
+// ReconnectErrors is the number of consecutive failed requests after which an endpoint is dialed again
+var ReconnectErrors = 3
+
+// Score penalties, expressed in milliseconds of latency
+const (
+	errorPenalty = 1000 // Per consecutive failed request
+	lagPenalty   = 250  // Per block behind the highest known endpoint
+)
+
+var ErrNoEndpoints = errors.New("no connected endpoints")
+
+// endpoint tracks the health of a single node the connection can use
+type endpoint struct {
+	url     string
+	client  *ethclient.Client
+	rpc     *rpc.Client   // Client for requests ethclient does not support
+	latency time.Duration // Moving average of request latency
+	errors  int           // Consecutive requests that failed on the node's side
+	height  *big.Int      // Latest block reported by the node
+	lock    sync.RWMutex
+}
+
+func (e *endpoint) dial(http bool) error {
+	var rpcClient *rpc.Client
+	var err error
+	// Start http or ws client
+	if http {
+		rpcClient, err = rpc.DialHTTP(e.url)
+	} else {
+		rpcClient, err = rpc.DialContext(context.Background(), e.url)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	old := e.client
+	e.client = ethclient.NewClient(rpcClient)
+	e.rpc = rpcClient
+	e.lock.Unlock()
+
+	// Requests still using the previous client fail and are retried on another endpoint
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// disconnect closes the client of the endpoint, so it is not used until it is dialed again
+func (e *endpoint) disconnect() {
+	e.lock.Lock()
+	client := e.client
+	e.client = nil
+	e.rpc = nil
+	e.lock.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+}
+
+func (e *endpoint) connected() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.client != nil
+}
+
+// getClient returns the client of the endpoint, which is nil if it never connected
+func (e *endpoint) getClient() *ethclient.Client {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.client
+}
+
+// getRPC returns the rpc client of the endpoint, which is nil if it never connected
+func (e *endpoint) getRPC() *rpc.Client {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.rpc
+}
+
+// failing returns true if the endpoint is not connected or its last ReconnectErrors requests failed
+func (e *endpoint) failing() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.client == nil || e.errors >= ReconnectErrors
+}
+
+// record updates the latency average and error count with the outcome of a request
+func (e *endpoint) record(latency time.Duration, err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if isNodeFault(err) {
+		e.errors++
+		return
+	}
+	e.errors = 0
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (e.latency*4 + latency) / 5
+	}
+}
+
+func (e *endpoint) setHeight(height *big.Int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.height == nil || height.Cmp(e.height) > 0 {
+		e.height = new(big.Int).Set(height)
+	}
+}
+
+func (e *endpoint) getHeight() *big.Int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.height
+}
+
+// score rates the endpoint relative to the highest block seen across all endpoints. Lower is healthier.
+func (e *endpoint) score(highest *big.Int) int64 {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	score := e.latency.Milliseconds() + int64(e.errors)*errorPenalty
+	if highest != nil && e.height != nil {
+		score += new(big.Int).Sub(highest, e.height).Int64() * lagPenalty
+	}
+	return score
+}
+
+// isNodeFault returns true if err indicates the node itself failed, rather than the node rejecting the request
+func isNodeFault(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
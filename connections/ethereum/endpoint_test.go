@@ -0,0 +1,122 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+type testRpcError struct{}
+
+func (testRpcError) Error() string  { return "execution reverted" }
+func (testRpcError) ErrorCode() int { return 3 }
+
+func TestIsNodeFault(t *testing.T) {
+	if isNodeFault(nil) || isNodeFault(ethereum.NotFound) || isNodeFault(testRpcError{}) {
+		t.Fatal("rejected requests should not count as node faults")
+	}
+	if !isNodeFault(errors.New("connection refused")) {
+		t.Fatal("expected transport error to count as node fault")
+	}
+}
+
+func TestHealthiestEndpoint(t *testing.T) {
+	conn := NewConnection("fast", false, nil, log15.Root(), nil, nil, nil, nil)
+	conn.AddEndpoints("slow", "lagging", "failing")
+	fast, slow, lagging, failing := conn.endpoints[0], conn.endpoints[1], conn.endpoints[2], conn.endpoints[3]
+	for _, e := range conn.endpoints {
+		// Clients are only checked for presence
+		e.client = &ethclient.Client{}
+		e.setHeight(big.NewInt(100))
+	}
+
+	fast.record(time.Millisecond*10, nil)
+	slow.record(time.Millisecond*300, nil)
+	lagging.record(time.Millisecond*10, nil)
+	lagging.height = big.NewInt(95)
+	failing.record(time.Millisecond*10, nil)
+	failing.record(0, errors.New("connection refused"))
+
+	order := conn.healthiest()
+	expected := []*endpoint{fast, slow, failing, lagging}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected %s at position %d, got %s", expected[i].url, i, order[i].url)
+		}
+	}
+
+	// A successful request resets the error count
+	failing.record(time.Millisecond*5, nil)
+	if conn.healthiest()[0] != failing {
+		t.Fatal("expected recovered endpoint to be the healthiest")
+	}
+}
+
+func TestEndpointFailing(t *testing.T) {
+	e := &endpoint{url: "node"}
+	if !e.failing() {
+		t.Fatal("expected unconnected endpoint to be failing")
+	}
+
+	e.client = &ethclient.Client{}
+	for i := 0; i < ReconnectErrors-1; i++ {
+		e.record(0, errors.New("connection refused"))
+	}
+	if e.failing() {
+		t.Fatal("unexpected failing endpoint before ReconnectErrors failed requests")
+	}
+	e.record(0, errors.New("connection refused"))
+	if !e.failing() {
+		t.Fatal("expected endpoint to be failing after ReconnectErrors failed requests")
+	}
+}
+
+func TestConnectEndpoint(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), "eth_chainId") {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"0x5"}`)
+	}))
+	defer node.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	conn := NewConnection(node.URL, true, nil, log15.Root(), nil, nil, nil, nil)
+	conn.AddEndpoints(broken.URL)
+
+	id, err := conn.connectEndpoint(conn.endpoints[0])
+	if err != nil || id.Cmp(big.NewInt(5)) != 0 {
+		t.Fatalf("expected chain ID 5, got %v, %v", id, err)
+	}
+
+	// An endpoint that dials but does not answer is not used
+	_, err = conn.connectEndpoint(conn.endpoints[1])
+	if err == nil {
+		t.Fatal("expected error for endpoint that does not answer")
+	}
+	if conn.endpoints[1].connected() {
+		t.Fatal("expected endpoint that does not answer to be disconnected")
+	}
+	if order := conn.healthiest(); len(order) != 1 || order[0] != conn.endpoints[0] {
+		t.Fatal("expected only the answering endpoint to be used")
+	}
+}
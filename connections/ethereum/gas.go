@@ -0,0 +1,47 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	eth "github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultGasMargin is the percentage added to gas estimates when no margin is configured
+const DefaultGasMargin uint64 = 20
+
+// SetGasMargin sets the percentage added to gas estimates. Must be called before Connection.Connect().
+func (c *Connection) SetGasMargin(percent uint64) {
+	c.gasMargin = percent
+}
+
+// EstimateGasLimit estimates the gas needed to call the contract at address to with data and adds the gas margin.
+// The result never exceeds the configured gas limit, which is also returned along with the error if the estimation
+// fails.
+func (c *Connection) EstimateGasLimit(to ethcommon.Address, data []byte) (uint64, error) {
+	ceiling := c.gasLimit.Uint64()
+	estimate, err := c.backend.EstimateGas(context.Background(), eth.CallMsg{
+		From: c.signer.Address(),
+		To:   &to,
+		Data: data,
+	})
+	if err != nil {
+		return ceiling, err
+	}
+	return applyGasMargin(estimate, c.gasMargin, ceiling), nil
+}
+
+// applyGasMargin raises estimate by margin percent, capped at ceiling
+func applyGasMargin(estimate, margin, ceiling uint64) uint64 {
+	limit := new(big.Int).SetUint64(estimate)
+	limit.Mul(limit, new(big.Int).SetUint64(100+margin))
+	limit.Div(limit, big.NewInt(100))
+	if !limit.IsUint64() || limit.Uint64() > ceiling {
+		return ceiling
+	}
+	return limit.Uint64()
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplyGasMargin(t *testing.T) {
+	testCases := []struct {
+		estimate, margin, ceiling uint64
+		expected                  uint64
+	}{
+		{estimate: 100000, margin: 20, ceiling: 6721975, expected: 120000},
+		{estimate: 100000, margin: 0, ceiling: 6721975, expected: 100000},
+		// Capped at the configured gas limit
+		{estimate: 6000000, margin: 20, ceiling: 6721975, expected: 6721975},
+		{estimate: math.MaxUint64 / 2, margin: 300, ceiling: math.MaxUint64, expected: math.MaxUint64},
+	}
+
+	for _, tc := range testCases {
+		limit := applyGasMargin(tc.estimate, tc.margin, tc.ceiling)
+		if limit != tc.expected {
+			t.Errorf("estimate %d with margin %d%%: expected %d, got %d", tc.estimate, tc.margin, tc.expected, limit)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/egs"
+)
+
+// HTTPTimeout is the timeout of requests to HTTP oracles
+var HTTPTimeout = time.Second * 10
+
+// Units of the prices returned by HTTP oracles
+var units = map[string]*big.Int{
+	"wei":  big.NewInt(1),
+	"gwei": big.NewInt(1000000000),
+}
+
+// ParseUnit returns the number of wei in unit, which is wei or gwei
+func ParseUnit(unit string) (*big.Int, error) {
+	if res, ok := units[strings.ToLower(unit)]; ok {
+		return res, nil
+	}
+	return nil, fmt.Errorf("unknown gas price unit %q", unit)
+}
+
+// HTTP is a GasPriceOracle reading the price from a JSON HTTP api. The path is a dot-separated list of object keys
+// and array indices, eg. "result.FastGasPrice" or "prices.0.fast". The value may be a number or a numeric string
+// and is multiplied by unit to get wei.
+type HTTP struct {
+	url    string
+	path   []string
+	unit   *big.Int
+	client *http.Client
+}
+
+func NewHTTP(url, path string, unit *big.Int) *HTTP {
+	return &HTTP{
+		url:    url,
+		path:   strings.Split(path, "."),
+		unit:   unit,
+		client: &http.Client{Timeout: HTTPTimeout},
+	}
+}
+
+func (h *HTTP) Name() string {
+	return HTTPOracle
+}
+
+func (h *HTTP) GasPrice(ctx context.Context) (*big.Int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", h.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", res.Status)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONPrice(body, h.path, h.unit)
+}
+
+// parseJSONPrice finds the value at path in body and converts it to wei
+func parseJSONPrice(body []byte, path []string, unit *big.Int) (*big.Int, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid array index %q", key)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("cannot look up %q in a %T", key, value)
+		}
+	}
+
+	var number string
+	switch v := value.(type) {
+	case json.Number:
+		number = v.String()
+	case string:
+		number = v
+	default:
+		return nil, fmt.Errorf("value at path is a %T, not a number", value)
+	}
+	price, ok := new(big.Float).SetPrec(256).SetString(number)
+	if !ok || price.Sign() == -1 {
+		return nil, fmt.Errorf("invalid gas price %q", number)
+	}
+	res, _ := price.Mul(price, new(big.Float).SetPrec(256).SetInt(unit)).Int(nil)
+	return res, nil
+}
+
+// EGS is a GasPriceOracle using EthGasStation. The service has been shut down, it is kept for existing configs.
+type EGS struct {
+	apiKey string
+	speed  string
+}
+
+func NewEGS(apiKey, speed string) *EGS {
+	return &EGS{apiKey: apiKey, speed: speed}
+}
+
+func (e *EGS) Name() string {
+	return EGSOracle
+}
+
+func (e *EGS) GasPrice(_ context.Context) (*big.Int, error) {
+	return egs.FetchGasPrice(e.apiKey, e.speed)
+}
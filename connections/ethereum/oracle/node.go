@@ -0,0 +1,87 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package oracle
+
+import (
+	"context"
+	"math/big"
+)
+
+// DefaultFeeHistoryBlocks is the default number of recent blocks fee percentiles are taken from
+const DefaultFeeHistoryBlocks uint64 = 20
+
+// FeeHistory is the result of eth_feeHistory
+type FeeHistory struct {
+	OldestBlock  *big.Int
+	Reward       [][]*big.Int // Effective priority fee per block at the requested percentiles
+	BaseFee      []*big.Int   // Base fee per block, including the block after the newest
+	GasUsedRatio []float64
+}
+
+// NodeBackend is the part of the node api used by the node oracle
+type NodeBackend interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blocks uint64, percentiles []float64) (*FeeHistory, error)
+}
+
+// Node is a GasPriceOracle using the node's suggestion. With a percentile set, the price is the base fee of the next
+// block plus the FeeHistoryTip at that percentile. On chains without a base fee this is the percentile of the gas
+// prices paid.
+type Node struct {
+	backend    NodeBackend
+	blocks     uint64
+	percentile float64
+}
+
+// NewNode creates a node oracle taking fees from the last blocks, a percentile of 0 uses eth_gasPrice
+func NewNode(backend NodeBackend, blocks uint64, percentile float64) *Node {
+	return &Node{backend: backend, blocks: blocks, percentile: percentile}
+}
+
+func (n *Node) Name() string {
+	return NodeOracle
+}
+
+func (n *Node) GasPrice(ctx context.Context) (*big.Int, error) {
+	if n.percentile == 0 {
+		return n.backend.SuggestGasPrice(ctx)
+	}
+
+	tip, baseFee, err := FeeHistoryTip(ctx, n.backend, n.blocks, n.percentile)
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		// Nothing was paid in the recent blocks to base a percentile on
+		return n.backend.SuggestGasPrice(ctx)
+	}
+	return tip.Add(tip, baseFee), nil
+}
+
+// FeeHistoryTip returns the median over the last blocks of the priority fee paid at percentile within each block,
+// along with the base fee of the next block. The tip is nil if the blocks contain no transactions.
+func FeeHistoryTip(ctx context.Context, backend NodeBackend, blocks uint64, percentile float64) (*big.Int, *big.Int, error) {
+	history, err := backend.FeeHistory(ctx, blocks, []float64{percentile})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseFee := new(big.Int)
+	if len(history.BaseFee) != 0 && history.BaseFee[len(history.BaseFee)-1] != nil {
+		baseFee.Set(history.BaseFee[len(history.BaseFee)-1])
+	}
+
+	var rewards []*big.Int
+	for i, r := range history.Reward {
+		// Empty blocks report a reward of 0, they say nothing about the fees needed
+		if len(r) == 0 || r[0] == nil || (i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0) {
+			continue
+		}
+		rewards = append(rewards, r[0])
+	}
+	if len(rewards) == 0 {
+		return nil, baseFee, nil
+	}
+	return median(rewards), baseFee, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+Package oracle provides the sources of gas price suggestions used by the ethereum connection.
+
+Built-in oracles are:
+  - node: the node's eth_gasPrice, or a percentile of recent fees from eth_feeHistory
+  - http: a number at a JSON path in the response of an HTTP endpoint
+  - fixed: a constant price
+  - egs: EthGasStation (deprecated, the service has been shut down)
+
+Several oracles can be combined with NewMedian.
+*/
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ChainSafe/log15"
+)
+
+// Oracle names used to select oracles in the chain config
+const (
+	NodeOracle  = "node"
+	HTTPOracle  = "http"
+	FixedOracle = "fixed"
+	EGSOracle   = "egs"
+)
+
+var ErrNoPrice = errors.New("no gas price oracle returned a price")
+
+// GasPriceOracle suggests a gas price in wei
+type GasPriceOracle interface {
+	Name() string
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// Median is a GasPriceOracle returning the median of the prices of several oracles. Oracles that fail are skipped.
+type Median struct {
+	oracles []GasPriceOracle
+	log     log15.Logger
+}
+
+// NewMedian combines oracles into a single GasPriceOracle
+func NewMedian(log log15.Logger, oracles ...GasPriceOracle) *Median {
+	return &Median{oracles: oracles, log: log}
+}
+
+func (m *Median) Name() string {
+	names := make([]string, len(m.oracles))
+	for i, o := range m.oracles {
+		names[i] = o.Name()
+	}
+	return fmt.Sprintf("median(%s)", strings.Join(names, ","))
+}
+
+// GasPrice queries every oracle and returns the median price. Returns ErrNoPrice if every oracle fails.
+func (m *Median) GasPrice(ctx context.Context) (*big.Int, error) {
+	var prices []*big.Int
+	for _, o := range m.oracles {
+		price, err := o.GasPrice(ctx)
+		if err != nil {
+			m.log.Warn("Gas price oracle failed", "oracle", o.Name(), "err", err)
+			continue
+		}
+		m.log.Trace("Gas price suggested", "oracle", o.Name(), "price", price)
+		prices = append(prices, price)
+	}
+	if len(prices) == 0 {
+		return nil, ErrNoPrice
+	}
+	return median(prices), nil
+}
+
+// median returns the middle value of prices, or the mean of the middle two for an even number of prices
+func median(prices []*big.Int) *big.Int {
+	sorted := make([]*big.Int, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) == -1 })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	res := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return res.Div(res, big.NewInt(2))
+}
+
+// Fixed is a GasPriceOracle that always returns the same price
+type Fixed struct {
+	price *big.Int
+}
+
+func NewFixed(price *big.Int) *Fixed {
+	return &Fixed{price: price}
+}
+
+func (f *Fixed) Name() string {
+	return FixedOracle
+}
+
+func (f *Fixed) GasPrice(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Set(f.price), nil
+}
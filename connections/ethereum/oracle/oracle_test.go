@@ -0,0 +1,133 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChainSafe/log15"
+)
+
+type failingOracle struct{}
+
+func (f failingOracle) Name() string { return "failing" }
+func (f failingOracle) GasPrice(_ context.Context) (*big.Int, error) {
+	return nil, errors.New("unavailable")
+}
+
+type fakeNode struct {
+	gasPrice *big.Int
+	history  *FeeHistory
+}
+
+func (f *fakeNode) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return f.gasPrice, nil
+}
+
+func (f *fakeNode) FeeHistory(_ context.Context, _ uint64, _ []float64) (*FeeHistory, error) {
+	return f.history, nil
+}
+
+func TestMedian(t *testing.T) {
+	testCases := []struct {
+		name     string
+		oracles  []GasPriceOracle
+		expected *big.Int
+	}{
+		{"single", []GasPriceOracle{NewFixed(big.NewInt(5))}, big.NewInt(5)},
+		{"odd", []GasPriceOracle{NewFixed(big.NewInt(30)), NewFixed(big.NewInt(10)), NewFixed(big.NewInt(20))}, big.NewInt(20)},
+		{"even", []GasPriceOracle{NewFixed(big.NewInt(40)), NewFixed(big.NewInt(10)), NewFixed(big.NewInt(20)), NewFixed(big.NewInt(31))}, big.NewInt(25)},
+		{"failures skipped", []GasPriceOracle{failingOracle{}, NewFixed(big.NewInt(7)), failingOracle{}}, big.NewInt(7)},
+	}
+
+	for _, tc := range testCases {
+		price, err := NewMedian(log15.Root(), tc.oracles...).GasPrice(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if price.Cmp(tc.expected) != 0 {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, price)
+		}
+	}
+
+	_, err := NewMedian(log15.Root(), failingOracle{}).GasPrice(context.Background())
+	if !errors.Is(err, ErrNoPrice) {
+		t.Fatalf("expected ErrNoPrice, got %v", err)
+	}
+}
+
+func TestNodeOracle(t *testing.T) {
+	node := &fakeNode{
+		gasPrice: big.NewInt(100),
+		history: &FeeHistory{
+			Reward:  [][]*big.Int{{big.NewInt(2)}, {big.NewInt(9)}, {big.NewInt(4)}},
+			BaseFee: []*big.Int{big.NewInt(50), big.NewInt(60), big.NewInt(70), big.NewInt(80)},
+		},
+	}
+
+	price, err := NewNode(node, DefaultFeeHistoryBlocks, 0).GasPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("expected eth_gasPrice of 100, got %s", price)
+	}
+
+	// Next base fee plus the median reward
+	price, err = NewNode(node, DefaultFeeHistoryBlocks, 60).GasPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(84)) != 0 {
+		t.Fatalf("expected 84, got %s", price)
+	}
+
+	node.history = &FeeHistory{}
+	price, err = NewNode(node, DefaultFeeHistoryBlocks, 60).GasPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("expected fallback to eth_gasPrice, got %s", price)
+	}
+}
+
+func TestHTTPOracle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`{"status":"1","result":{"SafeGasPrice":"20","FastGasPrice":"31.5"},"levels":[{"price":12}]}`))
+	}))
+	defer server.Close()
+
+	gwei, err := ParseUnit("gwei")
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := NewHTTP(server.URL, "result.FastGasPrice", gwei).GasPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(31500000000)) != 0 {
+		t.Fatalf("expected 31.5 gwei, got %s", price)
+	}
+
+	price, err = NewHTTP(server.URL, "levels.0.price", big.NewInt(1)).GasPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(12)) != 0 {
+		t.Fatalf("expected 12 wei, got %s", price)
+	}
+
+	for _, path := range []string{"result.Missing", "levels.1.price", "status.x", "result"} {
+		_, err = NewHTTP(server.URL, path, gwei).GasPrice(context.Background())
+		if err == nil {
+			t.Errorf("expected error for path %q", path)
+		}
+	}
+}
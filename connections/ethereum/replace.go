@@ -0,0 +1,105 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxBumpPercent is how much the fees of a replacement transaction are raised over the transaction it replaces
+var TxBumpPercent int64 = 20
+
+// MinTxBumpPercent is the minimum fee increase nodes accept for a replacement transaction
+const MinTxBumpPercent int64 = 10
+
+// ErrMaxGasPrice is returned when a transaction cannot be replaced because its fees are already at the maximum
+var ErrMaxGasPrice = errors.New("transaction fees are at the maximum gas price")
+
+// ReplaceTx resubmits tx with the same nonce and its fees raised by TxBumpPercent, up to the maximum gas price.
+// Returns ErrMaxGasPrice if the fees cannot be raised enough for nodes to accept the replacement.
+func (c *Connection) ReplaceTx(tx *types.Transaction) (*types.Transaction, error) {
+	c.optsLock.Lock()
+	maxGasPrice := c.maxGasPrice
+	from, signer := c.opts.From, c.opts.Signer
+	c.optsLock.Unlock()
+
+	var replacement *types.Transaction
+	if tx.Type() == types.DynamicFeeTxType {
+		tip, feeCap, err := bumpDynamicFees(tx.GasTipCap(), tx.GasFeeCap(), maxGasPrice)
+		if err != nil {
+			return nil, err
+		}
+		replacement = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   tx.ChainId(),
+			Nonce:     tx.Nonce(),
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		})
+	} else {
+		gasPrice, err := bumpGasPrice(tx.GasPrice(), maxGasPrice)
+		if err != nil {
+			return nil, err
+		}
+		replacement = types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		})
+	}
+
+	signed, err := signer(from, replacement)
+	if err != nil {
+		return nil, err
+	}
+	err = c.backend.SendTransaction(context.Background(), signed)
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+func bump(v *big.Int, percent int64) *big.Int {
+	res := new(big.Int).Mul(v, big.NewInt(100+percent))
+	return res.Div(res, big.NewInt(100))
+}
+
+// bumpGasPrice raises a legacy gas price by TxBumpPercent, capped at max
+func bumpGasPrice(gasPrice, max *big.Int) (*big.Int, error) {
+	bumped := bump(gasPrice, TxBumpPercent)
+	if bumped.Cmp(max) == 1 {
+		bumped = new(big.Int).Set(max)
+	}
+	if bumped.Cmp(bump(gasPrice, MinTxBumpPercent)) == -1 {
+		return nil, ErrMaxGasPrice
+	}
+	return bumped, nil
+}
+
+// bumpDynamicFees raises the tip and fee cap of a dynamic fee transaction by TxBumpPercent. The fee cap is capped at
+// max and the tip at the fee cap.
+func bumpDynamicFees(tip, feeCap, max *big.Int) (*big.Int, *big.Int, error) {
+	bumpedCap := bump(feeCap, TxBumpPercent)
+	if bumpedCap.Cmp(max) == 1 {
+		bumpedCap = new(big.Int).Set(max)
+	}
+	bumpedTip := bump(tip, TxBumpPercent)
+	if bumpedTip.Cmp(bumpedCap) == 1 {
+		bumpedTip = new(big.Int).Set(bumpedCap)
+	}
+	if bumpedCap.Cmp(bump(feeCap, MinTxBumpPercent)) == -1 || bumpedTip.Cmp(bump(tip, MinTxBumpPercent)) == -1 {
+		return nil, nil, ErrMaxGasPrice
+	}
+	return bumpedTip, bumpedCap, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBumpGasPrice(t *testing.T) {
+	price, err := bumpGasPrice(big.NewInt(100), big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(120)) != 0 {
+		t.Fatalf("expected 120, got %s", price)
+	}
+
+	// Capped at the maximum, which is still enough of an increase
+	price, err = bumpGasPrice(big.NewInt(100), big.NewInt(115))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(115)) != 0 {
+		t.Fatalf("expected 115, got %s", price)
+	}
+
+	_, err = bumpGasPrice(big.NewInt(100), big.NewInt(105))
+	if !errors.Is(err, ErrMaxGasPrice) {
+		t.Fatalf("expected ErrMaxGasPrice, got %v", err)
+	}
+}
+
+func TestBumpDynamicFees(t *testing.T) {
+	tip, feeCap, err := bumpDynamicFees(big.NewInt(10), big.NewInt(100), big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Cmp(big.NewInt(12)) != 0 || feeCap.Cmp(big.NewInt(120)) != 0 {
+		t.Fatalf("expected tip 12 and fee cap 120, got %s and %s", tip, feeCap)
+	}
+
+	// The tip never exceeds the fee cap
+	tip, feeCap, err = bumpDynamicFees(big.NewInt(100), big.NewInt(100), big.NewInt(112))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Cmp(big.NewInt(112)) != 0 || feeCap.Cmp(big.NewInt(112)) != 0 {
+		t.Fatalf("expected tip and fee cap of 112, got %s and %s", tip, feeCap)
+	}
+
+	_, _, err = bumpDynamicFees(big.NewInt(10), big.NewInt(100), big.NewInt(100))
+	if !errors.Is(err, ErrMaxGasPrice) {
+		t.Fatalf("expected ErrMaxGasPrice, got %v", err)
+	}
+}
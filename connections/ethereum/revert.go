@@ -0,0 +1,56 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertReason returns why the mined transaction tx reverted. The transaction is re-executed as a call on the state
+// before its block, so the reason is a best effort if other transactions in the block affected the outcome.
+func (c *Connection) RevertReason(tx *types.Transaction, receipt *types.Receipt) string {
+	if receipt.GasUsed == tx.Gas() {
+		return "out of gas"
+	}
+
+	call := eth.CallMsg{
+		From:  c.signer.Address(),
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	block := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	err := c.call(func(client *ethclient.Client) error {
+		_, err := client.CallContract(context.Background(), call, block)
+		return err
+	})
+	if err == nil {
+		return "unknown, the call succeeds when re-executed"
+	}
+	return decodeRevert(err)
+}
+
+// decodeRevert extracts the revert reason from the error of a call, falling back to the error message
+func decodeRevert(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			reason, unpackErr := abi.UnpackRevert(ethcommon.FromHex(data))
+			if unpackErr == nil {
+				return reason
+			}
+		}
+	}
+	return err.Error()
+}
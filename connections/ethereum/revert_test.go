@@ -0,0 +1,39 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"errors"
+	"testing"
+)
+
+type testDataError struct {
+	data interface{}
+}
+
+func (e testDataError) Error() string          { return "execution reverted" }
+func (e testDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevert(t *testing.T) {
+	// Error(string) encoding of "Pausable: paused"
+	data := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000010" +
+		"5061757361626c653a2070617573656400000000000000000000000000000000"
+
+	reason := decodeRevert(testDataError{data: data})
+	if reason != "Pausable: paused" {
+		t.Fatalf("expected decoded reason, got %q", reason)
+	}
+
+	reason = decodeRevert(testDataError{data: "0x1234"})
+	if reason != "execution reverted" {
+		t.Fatalf("expected error message for undecodable data, got %q", reason)
+	}
+
+	reason = decodeRevert(errors.New("connection refused"))
+	if reason != "connection refused" {
+		t.Fatalf("expected error message, got %q", reason)
+	}
+}
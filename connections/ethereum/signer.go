@@ -0,0 +1,157 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/UltronFoundationDev/chainbridge-utils/crypto/secp256k1"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var RemoteSignerTimeout = time.Second * 30
+
+// Signer signs transactions on behalf of the relayer's account
+type Signer interface {
+	Address() ethcommon.Address
+	SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error)
+}
+
+// KeystoreSigner signs with a key loaded from the local keystore
+type KeystoreSigner struct {
+	kp *secp256k1.Keypair
+}
+
+func NewKeystoreSigner(kp *secp256k1.Keypair) *KeystoreSigner {
+	return &KeystoreSigner{kp: kp}
+}
+
+func (s *KeystoreSigner) Address() ethcommon.Address {
+	return s.kp.CommonAddress()
+}
+
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainId), s.kp.PrivateKey())
+}
+
+// RemoteSigner signs by sending eth_signTransaction to a signer such as Clef or web3signer, so the key never
+// resides on the relayer host
+type RemoteSigner struct {
+	url     string
+	address ethcommon.Address
+	client  *rpc.Client
+}
+
+// NewRemoteSigner returns a signer for address using the signer at url
+func NewRemoteSigner(url string, address ethcommon.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialHTTP(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSigner{url: url, address: address, client: client}, nil
+}
+
+func (s *RemoteSigner) Address() ethcommon.Address {
+	return s.address
+}
+
+// signTxArgs are the eth_signTransaction parameters accepted by Clef and web3signer
+type signTxArgs struct {
+	From                 ethcommon.Address  `json:"from"`
+	To                   *ethcommon.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64     `json:"gas"`
+	GasPrice             *hexutil.Big       `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big       `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big       `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big        `json:"value"`
+	Nonce                hexutil.Uint64     `json:"nonce"`
+	Data                 hexutil.Bytes      `json:"data"`
+	ChainID              *hexutil.Big       `json:"chainId,omitempty"`
+}
+
+// SignTx requests a signature for tx from the remote signer. The signed transaction is checked to be signed by the
+// expected account and to match tx, so a misbehaving signer cannot substitute another transaction.
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainId),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteSignerTimeout)
+	defer cancel()
+
+	var res json.RawMessage
+	err := s.client.CallContext(ctx, &res, "eth_signTransaction", args)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer %s: %w", s.url, err)
+	}
+
+	raw, err := decodeSignResult(res)
+	if err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	err = signed.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode signed transaction: %w", err)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainId), signed)
+	if err != nil {
+		return nil, err
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed for %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if !sameTx(tx, signed) {
+		return nil, fmt.Errorf("remote signer returned a transaction that does not match the request")
+	}
+	return signed, nil
+}
+
+// decodeSignResult extracts the raw transaction from an eth_signTransaction response. Clef responds with an object
+// containing the raw transaction, web3signer with the raw transaction itself.
+func decodeSignResult(res json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(res, &raw); err == nil {
+		return raw, nil
+	}
+
+	var obj struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(res, &obj); err != nil || len(obj.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected eth_signTransaction response: %s", string(res))
+	}
+	return obj.Raw, nil
+}
+
+func sameTx(a, b *types.Transaction) bool {
+	sameTo := (a.To() == nil && b.To() == nil) || (a.To() != nil && b.To() != nil && *a.To() == *b.To())
+	return sameTo &&
+		a.Nonce() == b.Nonce() &&
+		a.Gas() == b.Gas() &&
+		a.Value().Cmp(b.Value()) == 0 &&
+		a.GasFeeCap().Cmp(b.GasFeeCap()) == 0 &&
+		a.GasTipCap().Cmp(b.GasTipCap()) == 0 &&
+		string(a.Data()) == string(b.Data())
+}
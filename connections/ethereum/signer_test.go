@@ -0,0 +1,103 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// newTestSignerServer serves eth_signTransaction by signing with key, responding in the style of Clef or web3signer
+func newTestSignerServer(t *testing.T, key *ecdsa.PrivateKey, clef bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage `json:"id"`
+			Params []signTxArgs    `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		args := req.Params[0]
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		})
+		signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		raw, _ := signed.MarshalBinary()
+
+		var result interface{} = hexutil.Bytes(raw)
+		if clef {
+			result = map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result})
+	}))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := ethcrypto.GenerateKey()
+	address := ethcrypto.PubkeyToAddress(key.PublicKey)
+	chainId := big.NewInt(5)
+	to := ethcommon.HexToAddress("0x1234")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0xab},
+	})
+
+	for _, clef := range []bool{true, false} {
+		srv := newTestSignerServer(t, key, clef)
+		signer, err := NewRemoteSigner(srv.URL, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signed, err := signer.SignTx(tx, chainId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signed.Nonce() != 7 || string(signed.Data()) != string([]byte{0xab}) {
+			t.Fatalf("unexpected signed transaction: %#v", signed)
+		}
+		srv.Close()
+	}
+
+	// A signer holding another key must be rejected
+	other, _ := ethcrypto.GenerateKey()
+	srv := newTestSignerServer(t, other, false)
+	defer srv.Close()
+	signer, err := NewRemoteSigner(srv.URL, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = signer.SignTx(tx, chainId)
+	if err == nil {
+		t.Fatal("expected signature from unexpected account to be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
+)
+
+// feeBackend is the part of the node api fee suggestions are based on
+type feeBackend interface {
+	oracle.NodeBackend
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// SetTipStrategy makes the priority fee of dynamic fee transactions the median over the last blocks of the fee paid
+// at percentile, multiplied by the gas multiplier. A percentile of 0 keeps using the node's suggestion. Either way the
+// fee is kept between minTip and maxTip, which may be nil. Must be called before Connection.Connect().
+func (c *Connection) SetTipStrategy(blocks uint64, percentile float64, minTip, maxTip *big.Int) {
+	c.feeHistoryBlocks = blocks
+	c.tipPercentile = percentile
+	c.minTip = minTip
+	c.maxTip = maxTip
+}
+
+// suggestGasTipCap returns the priority fee for a dynamic fee transaction according to the tip strategy
+func (c *Connection) suggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var tip *big.Int
+	if c.tipPercentile != 0 {
+		var err error
+		tip, _, err = oracle.FeeHistoryTip(ctx, c.fees, c.feeHistoryBlocks, c.tipPercentile)
+		if err != nil {
+			return nil, err
+		}
+		if tip != nil {
+			tip = multiplyGasPrice(tip, c.gasMultiplier)
+		}
+	}
+
+	// Without a percentile, or without recent transactions to take one from, the node decides
+	if tip == nil {
+		var err error
+		tip, err = c.fees.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.minTip != nil && tip.Cmp(c.minTip) == -1 {
+		return new(big.Int).Set(c.minTip), nil
+	} else if c.maxTip != nil && tip.Cmp(c.maxTip) == 1 {
+		return new(big.Int).Set(c.maxTip), nil
+	}
+	return tip, nil
+}
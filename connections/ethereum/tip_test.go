@@ -0,0 +1,125 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/log15"
+	"github.com/UltronFoundationDev/chainbridge/connections/ethereum/oracle"
+)
+
+// fakeFees is a feeBackend with fixed responses
+type fakeFees struct {
+	tipCap      *big.Int
+	history     *oracle.FeeHistory
+	blocks      uint64
+	percentiles []float64
+}
+
+func (f *fakeFees) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return nil, nil
+}
+
+func (f *fakeFees) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return f.tipCap, nil
+}
+
+func (f *fakeFees) FeeHistory(_ context.Context, blocks uint64, percentiles []float64) (*oracle.FeeHistory, error) {
+	f.blocks = blocks
+	f.percentiles = percentiles
+	return f.history, nil
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1000000000))
+}
+
+func newTipTestConnection(fees feeBackend, multiplier float64) *Connection {
+	return &Connection{
+		maxGasPrice:      gwei(500),
+		gasMultiplier:    big.NewFloat(multiplier),
+		fees:             fees,
+		feeHistoryBlocks: oracle.DefaultFeeHistoryBlocks,
+		log:              log15.Root(),
+	}
+}
+
+func TestSuggestGasTipCap(t *testing.T) {
+	fees := &fakeFees{
+		tipCap: big.NewInt(0),
+		history: &oracle.FeeHistory{
+			Reward:       [][]*big.Int{{gwei(1)}, {big.NewInt(0)}, {gwei(3)}, {gwei(2)}},
+			BaseFee:      []*big.Int{gwei(10), gwei(10), gwei(10), gwei(10), gwei(11)},
+			GasUsedRatio: []float64{0.5, 0, 0.7, 0.4},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		percentile     float64
+		multiplier     float64
+		minTip, maxTip *big.Int
+		expected       *big.Int
+	}{
+		{name: "node suggestion", percentile: 0, multiplier: 1, expected: big.NewInt(0)},
+		{name: "node suggestion clamped", percentile: 0, multiplier: 1, minTip: gwei(1), expected: gwei(1)},
+		// The empty block is ignored, the median of 1, 3 and 2 gwei is 2 gwei
+		{name: "percentile", percentile: 50, multiplier: 1, expected: gwei(2)},
+		{name: "multiplier", percentile: 50, multiplier: 1.5, expected: gwei(3)},
+		{name: "max tip", percentile: 50, multiplier: 1.5, maxTip: gwei(2), expected: gwei(2)},
+		{name: "min tip", percentile: 50, multiplier: 1, minTip: gwei(5), maxTip: gwei(10), expected: gwei(5)},
+	}
+
+	for _, tc := range testCases {
+		conn := newTipTestConnection(fees, tc.multiplier)
+		conn.SetTipStrategy(oracle.DefaultFeeHistoryBlocks, tc.percentile, tc.minTip, tc.maxTip)
+
+		tip, err := conn.suggestGasTipCap(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if tip.Cmp(tc.expected) != 0 {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, tip)
+		}
+	}
+
+	if fees.blocks != oracle.DefaultFeeHistoryBlocks || len(fees.percentiles) != 1 || fees.percentiles[0] != 50 {
+		t.Fatalf("unexpected fee history request for %d blocks at %v", fees.blocks, fees.percentiles)
+	}
+}
+
+func TestEstimateGasLondonFeeHistory(t *testing.T) {
+	fees := &fakeFees{
+		// A node suggesting an absurd tip is ignored when a percentile is configured
+		tipCap: gwei(10000),
+		history: &oracle.FeeHistory{
+			Reward:       [][]*big.Int{{gwei(2)}},
+			GasUsedRatio: []float64{0.5},
+		},
+	}
+	conn := newTipTestConnection(fees, 1)
+	conn.SetTipStrategy(oracle.DefaultFeeHistoryBlocks, 60, nil, nil)
+
+	tip, feeCap, err := conn.EstimateGasLondon(context.Background(), gwei(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Cmp(gwei(2)) != 0 || feeCap.Cmp(gwei(42)) != 0 {
+		t.Fatalf("expected tip of 2 gwei and fee cap of 42 gwei, got %s and %s", tip, feeCap)
+	}
+
+	// Empty blocks fall back to the node suggestion, clamped to the maximum tip
+	fees.history = &oracle.FeeHistory{Reward: [][]*big.Int{{big.NewInt(0)}}, GasUsedRatio: []float64{0}}
+	conn.SetTipStrategy(oracle.DefaultFeeHistoryBlocks, 60, nil, gwei(5))
+	tip, _, err = conn.EstimateGasLondon(context.Background(), gwei(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Cmp(gwei(5)) != 0 {
+		t.Fatalf("expected tip of 5 gwei, got %s", tip)
+	}
+}